@@ -7,6 +7,8 @@ import (
 
 type Node interface {
 	String() string
+	Pos() token.Position // position of the first char of the node
+	End() token.Position // position just after the node
 }
 
 type Program struct {
@@ -14,18 +16,18 @@ type Program struct {
 }
 
 type Statement interface {
-	String() string
+	Node
 }
 
 type Expression interface {
 	// IntegerLiteral, InfixExpression, PrefixExpression
 	token() *token.Token
-	String() string
+	Node
 }
 
 type AssignStatement struct {
 	Token      token.Token // token.assign
-	Identifier string
+	Target     Expression  // Identifier or IndexExpression
 	Expression Expression
 }
 
@@ -43,6 +45,7 @@ type IncrementDecrementStatement struct {
 	Token       token.Token
 	Identifier  string
 	IsIncrement bool
+	EndToken    token.Token // 大D or 細D
 }
 
 type IntegerLiteral struct {
@@ -50,20 +53,52 @@ type IntegerLiteral struct {
 	Value int
 }
 
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
 type StringLiteral struct {
 	Token token.Token
 	Value string
 }
 
+// TemplateLiteral is a string with {expression} parts, text parts are
+// StringLiterals
+type TemplateLiteral struct {
+	Token token.Token // token.template
+	Parts []Expression
+}
+
 type ArrayLiteral struct {
-	Token token.Token
-	Items []Expression
+	Token    token.Token
+	Items    []Expression
+	EndToken token.Token // token.close_bracket
+}
+
+type HashLiteral struct {
+	Token    token.Token // token.open_brace
+	Pairs    []HashPair
+	EndToken token.Token // token.close_brace
+}
+
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
+// MemberExpression looks up Member in a module or hash, 數學.平方 or 數學 嘅 平方
+type MemberExpression struct {
+	Token  token.Token // token.dot
+	Object Expression
+	Member *Identifier
 }
 
 type IndexExpression struct {
-	Token token.Token
-	Left  Expression
-	Index Expression
+	Token    token.Token
+	Left     Expression
+	Index    Expression
+	EndToken token.Token // token.close_bracket
 }
 
 type Boolean struct {
@@ -82,9 +117,16 @@ type Identifier struct {
 	Token token.Token
 }
 
+type FunctionLiteral struct {
+	Token      token.Token // token.function
+	Parameters []Identifier
+	Body       *BlockStatement
+}
+
 type FunctionCallExpression struct {
-	Identifier *Identifier
+	Function   Expression // identifier or any expression evaluating to a function
 	Parameters []Expression
+	EndToken   token.Token // token.close_paren
 }
 
 type PrefixExpression struct {
@@ -102,19 +144,84 @@ type IfExpression struct {
 	Token       token.Token
 	Condition   Expression
 	Consequence *BlockStatement
+	ElseIfs     []*ElseIf
 	Alternative *BlockStatement
 }
 
+type ElseIf struct {
+	Token       token.Token // token.else_if or token.if
+	Condition   Expression
+	Consequence *BlockStatement
+}
+
+// TryExpression runs Catch with the error bound to ErrorName, which may be nil,
+// when Body raises an error
+type TryExpression struct {
+	Token     token.Token
+	Body      *BlockStatement
+	ErrorName *Identifier
+	Catch     *BlockStatement
+}
+
+type MatchExpression struct {
+	Token    token.Token
+	Value    Expression
+	Arms     []*MatchArm
+	EndToken token.Token // token.close_brace
+}
+
+// MatchArm runs Body when Pattern matches, a nil Pattern is the default arm
+type MatchArm struct {
+	Pattern Expression
+	Body    *BlockStatement
+}
+
 type WhileLoop struct {
 	Token     token.Token
 	Condition Expression
 	Body      *BlockStatement
 }
 
+type ForEachLoop struct {
+	Token    token.Token
+	Variable *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+// ImportStatement evaluates the file at Path and binds it as a module to Name
+type ImportStatement struct {
+	Token token.Token
+	Path  *StringLiteral
+	Name  *Identifier
+}
+
+type BreakStatement struct {
+	Token token.Token // token.break
+}
+
+type ContinueStatement struct {
+	Token token.Token // token.continue
+}
+
 type BlockStatement struct {
+	Token      token.Token // token.open_brace
 	Statements []Statement
+	EndToken   token.Token // token.close_brace
 }
 
+func (p *Program) Pos() token.Position {
+	if len(p.Statements) == 0 {
+		return token.Position{}
+	}
+	return p.Statements[0].Pos()
+}
+func (p *Program) End() token.Position {
+	if len(p.Statements) == 0 {
+		return token.Position{}
+	}
+	return p.Statements[len(p.Statements)-1].End()
+}
 func (p *Program) String() string {
 	buff := bytes.Buffer{}
 	for _, s := range p.Statements {
@@ -126,20 +233,74 @@ func (p *Program) String() string {
 func (il *IntegerLiteral) token() *token.Token {
 	return &il.Token
 }
+func (il *IntegerLiteral) Pos() token.Position {
+	return il.Token.Pos
+}
+func (il *IntegerLiteral) End() token.Position {
+	return il.Token.End
+}
 func (il *IntegerLiteral) String() string {
 	return il.Token.TokenLiteral
 }
 
+func (fl *FloatLiteral) token() *token.Token {
+	return &fl.Token
+}
+func (fl *FloatLiteral) Pos() token.Position {
+	return fl.Token.Pos
+}
+func (fl *FloatLiteral) End() token.Position {
+	return fl.Token.End
+}
+func (fl *FloatLiteral) String() string {
+	return fl.Token.TokenLiteral
+}
+
 func (sl *StringLiteral) token() *token.Token {
 	return &sl.Token
 }
+func (sl *StringLiteral) Pos() token.Position {
+	return sl.Token.Pos
+}
+func (sl *StringLiteral) End() token.Position {
+	return sl.Token.End
+}
 func (sl *StringLiteral) String() string {
 	return `"` + sl.Token.TokenLiteral + `"`
 }
 
+func (tl *TemplateLiteral) token() *token.Token {
+	return &tl.Token
+}
+func (tl *TemplateLiteral) Pos() token.Position {
+	return tl.Token.Pos
+}
+func (tl *TemplateLiteral) End() token.Position {
+	return tl.Token.End
+}
+func (tl *TemplateLiteral) String() string {
+	buff := bytes.Buffer{}
+	buff.WriteString("\"")
+	for _, part := range tl.Parts {
+		if text, ok := part.(*StringLiteral); ok {
+			buff.WriteString(text.Value)
+		} else {
+			buff.WriteString("{" + part.String() + "}")
+		}
+	}
+	buff.WriteString("\"")
+	return buff.String()
+}
+
 func (al *ArrayLiteral) token() *token.Token {
 	return &al.Token
 }
+func (al *ArrayLiteral) Pos() token.Position {
+	return al.Token.Pos
+}
+func (al *ArrayLiteral) End() token.Position {
+	return al.EndToken.End
+}
 func (al *ArrayLiteral) String() string {
 	buff := bytes.Buffer{}
 	buff.WriteString("[")
@@ -153,9 +314,37 @@ func (al *ArrayLiteral) String() string {
 	return buff.String()
 }
 
+func (hl *HashLiteral) token() *token.Token {
+	return &hl.Token
+}
+func (hl *HashLiteral) Pos() token.Position {
+	return hl.Token.Pos
+}
+func (hl *HashLiteral) End() token.Position {
+	return hl.EndToken.End
+}
+func (hl *HashLiteral) String() string {
+	buff := bytes.Buffer{}
+	buff.WriteString("{")
+	for i, pair := range hl.Pairs {
+		if i != 0 {
+			buff.WriteString(", ")
+		}
+		buff.WriteString(pair.Key.String() + ": " + pair.Value.String())
+	}
+	buff.WriteString("}")
+	return buff.String()
+}
+
 func (ie *IndexExpression) token() *token.Token {
 	return &ie.Token
 }
+func (ie *IndexExpression) Pos() token.Position {
+	return ie.Left.Pos()
+}
+func (ie *IndexExpression) End() token.Position {
+	return ie.EndToken.End
+}
 func (ie *IndexExpression) String() string {
 	return ie.Left.String() + "[" + ie.Index.String() + "]"
 }
@@ -163,10 +352,22 @@ func (ie *IndexExpression) String() string {
 func (b *Boolean) token() *token.Token {
 	return &b.Token
 }
+func (b *Boolean) Pos() token.Position {
+	return b.Token.Pos
+}
+func (b *Boolean) End() token.Position {
+	return b.Token.End
+}
 func (b *Boolean) String() string {
 	return b.Token.TokenLiteral
 }
 
+func (fd *FunctionDefStatment) Pos() token.Position {
+	return fd.Token.Pos
+}
+func (fd *FunctionDefStatment) End() token.Position {
+	return fd.Body.End()
+}
 func (fd *FunctionDefStatment) String() string {
 	buff := bytes.Buffer{}
 	buff.WriteString(fd.Token.TokenLiteral + " ")
@@ -187,28 +388,74 @@ func (fd *FunctionDefStatment) String() string {
 func (i *Identifier) token() *token.Token {
 	return &i.Token
 }
+func (i *Identifier) Pos() token.Position {
+	return i.Token.Pos
+}
+func (i *Identifier) End() token.Position {
+	return i.Token.End
+}
 func (i *Identifier) String() string {
 	return i.Token.TokenLiteral
 }
 
+func (fl *FunctionLiteral) token() *token.Token {
+	return &fl.Token
+}
+func (fl *FunctionLiteral) Pos() token.Position {
+	return fl.Token.Pos
+}
+func (fl *FunctionLiteral) End() token.Position {
+	return fl.Body.End()
+}
+func (fl *FunctionLiteral) String() string {
+	buff := bytes.Buffer{}
+	buff.WriteString(fl.Token.TokenLiteral + "(")
+	for i, param := range fl.Parameters {
+		if i != 0 {
+			buff.WriteString(",")
+		}
+		buff.WriteString(param.String())
+	}
+	buff.WriteString(") {")
+	buff.WriteString(fl.Body.String())
+	buff.WriteString("}")
+	return buff.String()
+}
+
 func (fe *FunctionCallExpression) token() *token.Token {
-	return &fe.Identifier.Token
+	return fe.Function.token()
+}
+func (fe *FunctionCallExpression) Pos() token.Position {
+	return fe.Function.Pos()
+}
+func (fe *FunctionCallExpression) End() token.Position {
+	return fe.EndToken.End
 }
 func (fe *FunctionCallExpression) String() string {
 	buff := bytes.Buffer{}
-	buff.WriteString(fe.token().TokenLiteral + "(")
+	buff.WriteString(fe.Function.String() + "(")
 	for i, param := range fe.Parameters {
 		if i != 0 {
 			buff.WriteString(", ")
 		}
 		buff.WriteString(param.String())
 	}
+	buff.WriteString(")")
 	return buff.String()
 }
 
 func (pe *PrefixExpression) token() *token.Token {
 	return &pe.PrefixToken
 }
+func (pe *PrefixExpression) Pos() token.Position {
+	return pe.PrefixToken.Pos
+}
+func (pe *PrefixExpression) End() token.Position {
+	if pe.Right == nil {
+		return pe.PrefixToken.End
+	}
+	return pe.Right.End()
+}
 func (pe *PrefixExpression) String() string {
 	return pe.PrefixToken.TokenLiteral + pe.Right.String()
 }
@@ -216,6 +463,18 @@ func (pe *PrefixExpression) String() string {
 func (ie *InfixExpression) token() *token.Token {
 	return &ie.Infix
 }
+func (ie *InfixExpression) Pos() token.Position {
+	if ie.Left == nil {
+		return ie.Infix.Pos
+	}
+	return ie.Left.Pos()
+}
+func (ie *InfixExpression) End() token.Position {
+	if ie.Right == nil {
+		return ie.Infix.End
+	}
+	return ie.Right.End()
+}
 func (ie *InfixExpression) String() string {
 	return "(" + ie.Left.String() + " " + ie.Infix.TokenLiteral + " " + ie.Right.String() + ")"
 }
@@ -223,11 +482,28 @@ func (ie *InfixExpression) String() string {
 func (ie *IfExpression) token() *token.Token {
 	return &ie.Token
 }
+func (ie *IfExpression) Pos() token.Position {
+	return ie.Token.Pos
+}
+func (ie *IfExpression) End() token.Position {
+	if ie.Alternative != nil {
+		return ie.Alternative.End()
+	}
+	if len(ie.ElseIfs) > 0 {
+		return ie.ElseIfs[len(ie.ElseIfs)-1].Consequence.End()
+	}
+	return ie.Consequence.End()
+}
 func (ie *IfExpression) String() string {
 	buff := bytes.Buffer{}
 	buff.WriteString("if")
 	buff.WriteString(ie.Condition.String())
 	buff.WriteString(ie.Consequence.String())
+	for _, elseIf := range ie.ElseIfs {
+		buff.WriteString("else if")
+		buff.WriteString(elseIf.Condition.String())
+		buff.WriteString(elseIf.Consequence.String())
+	}
 	if ie.Alternative == nil {
 		return buff.String()
 	}
@@ -236,6 +512,73 @@ func (ie *IfExpression) String() string {
 	return buff.String()
 }
 
+func (me *MemberExpression) token() *token.Token {
+	return &me.Token
+}
+func (me *MemberExpression) Pos() token.Position {
+	return me.Object.Pos()
+}
+func (me *MemberExpression) End() token.Position {
+	return me.Member.End()
+}
+func (me *MemberExpression) String() string {
+	return "(" + me.Object.String() + "." + me.Member.String() + ")"
+}
+
+func (te *TryExpression) token() *token.Token {
+	return &te.Token
+}
+func (te *TryExpression) Pos() token.Position {
+	return te.Token.Pos
+}
+func (te *TryExpression) End() token.Position {
+	return te.Catch.End()
+}
+func (te *TryExpression) String() string {
+	buff := bytes.Buffer{}
+	buff.WriteString("try")
+	buff.WriteString(te.Body.String())
+	buff.WriteString("catch")
+	if te.ErrorName != nil {
+		buff.WriteString("(" + te.ErrorName.String() + ")")
+	}
+	buff.WriteString(te.Catch.String())
+	return buff.String()
+}
+
+func (me *MatchExpression) token() *token.Token {
+	return &me.Token
+}
+func (me *MatchExpression) Pos() token.Position {
+	return me.Token.Pos
+}
+func (me *MatchExpression) End() token.Position {
+	return me.EndToken.End
+}
+func (me *MatchExpression) String() string {
+	buff := bytes.Buffer{}
+	buff.WriteString("match(")
+	buff.WriteString(me.Value.String())
+	buff.WriteString("){")
+	for _, arm := range me.Arms {
+		if arm.Pattern == nil {
+			buff.WriteString("default")
+		} else {
+			buff.WriteString(arm.Pattern.String())
+		}
+		buff.WriteString(" => ")
+		buff.WriteString(arm.Body.String())
+	}
+	buff.WriteString("}")
+	return buff.String()
+}
+
+func (bs *BlockStatement) Pos() token.Position {
+	return bs.Token.Pos
+}
+func (bs *BlockStatement) End() token.Position {
+	return bs.EndToken.End
+}
 func (bs *BlockStatement) String() string {
 	buff := bytes.Buffer{}
 	for _, s := range bs.Statements {
@@ -244,18 +587,48 @@ func (bs *BlockStatement) String() string {
 	return buff.String()
 }
 
+func (as *AssignStatement) Pos() token.Position {
+	return as.Token.Pos
+}
+func (as *AssignStatement) End() token.Position {
+	return as.Target.End()
+}
 func (as *AssignStatement) String() string {
-	return as.Token.TokenLiteral + as.Expression.String() + "-> " + as.Identifier
+	return as.Token.TokenLiteral + as.Expression.String() + "-> " + as.Target.String()
 }
 
+func (rs *ReturnStatement) Pos() token.Position {
+	return rs.Token.Pos
+}
+func (rs *ReturnStatement) End() token.Position {
+	if rs.Expression == nil {
+		return rs.Token.End
+	}
+	return rs.Expression.End()
+}
 func (rs *ReturnStatement) String() string {
 	return rs.Token.TokenLiteral + rs.Expression.String()
 }
 
+func (es *ExpressionStatement) Pos() token.Position {
+	return es.Token.Pos
+}
+func (es *ExpressionStatement) End() token.Position {
+	if es.Expression == nil {
+		return es.Token.End
+	}
+	return es.Expression.End()
+}
 func (es *ExpressionStatement) String() string {
 	return es.Expression.String()
 }
 
+func (is *IncrementDecrementStatement) Pos() token.Position {
+	return is.Token.Pos
+}
+func (is *IncrementDecrementStatement) End() token.Position {
+	return is.EndToken.End
+}
 func (is *IncrementDecrementStatement) String() string {
 	if is.IsIncrement {
 		return is.Identifier + "++"
@@ -266,6 +639,12 @@ func (is *IncrementDecrementStatement) String() string {
 func (wl *WhileLoop) token() *token.Token {
 	return &wl.Token
 }
+func (wl *WhileLoop) Pos() token.Position {
+	return wl.Token.Pos
+}
+func (wl *WhileLoop) End() token.Position {
+	return wl.Body.End()
+}
 func (wl *WhileLoop) String() string {
 	buff := bytes.Buffer{}
 	buff.WriteString("while")
@@ -273,3 +652,52 @@ func (wl *WhileLoop) String() string {
 	buff.WriteString(wl.Body.String())
 	return buff.String()
 }
+
+func (fl *ForEachLoop) token() *token.Token {
+	return &fl.Token
+}
+func (fl *ForEachLoop) Pos() token.Position {
+	return fl.Token.Pos
+}
+func (fl *ForEachLoop) End() token.Position {
+	return fl.Body.End()
+}
+func (fl *ForEachLoop) String() string {
+	buff := bytes.Buffer{}
+	buff.WriteString("for ")
+	buff.WriteString(fl.Variable.String())
+	buff.WriteString(" in ")
+	buff.WriteString(fl.Iterable.String())
+	buff.WriteString(fl.Body.String())
+	return buff.String()
+}
+
+func (is *ImportStatement) Pos() token.Position {
+	return is.Token.Pos
+}
+func (is *ImportStatement) End() token.Position {
+	return is.Name.End()
+}
+func (is *ImportStatement) String() string {
+	return "import " + is.Path.String() + " as " + is.Name.String()
+}
+
+func (bs *BreakStatement) Pos() token.Position {
+	return bs.Token.Pos
+}
+func (bs *BreakStatement) End() token.Position {
+	return bs.Token.End
+}
+func (bs *BreakStatement) String() string {
+	return bs.Token.TokenLiteral
+}
+
+func (cs *ContinueStatement) Pos() token.Position {
+	return cs.Token.Pos
+}
+func (cs *ContinueStatement) End() token.Position {
+	return cs.Token.End
+}
+func (cs *ContinueStatement) String() string {
+	return cs.Token.TokenLiteral
+}
@@ -0,0 +1,186 @@
+// Package cantolang runs Cantolang programs from Go.
+//
+//	in := cantolang.New()
+//	in.Set("名", "阿明")
+//	in.Run(`聽到 打招呼（x） 嘅話，就「 俾我 “你好” + x。 」`)
+//	greeting, err := in.Call("打招呼", "世界")
+package cantolang
+
+import (
+	"cantolang/evaluator"
+	"cantolang/lexer"
+	"cantolang/object"
+	"cantolang/parser"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Interpreter keeps the global variables of the programs it runs, so later
+// runs and calls can use what earlier runs defined.
+type Interpreter struct {
+	env *object.Environment
+}
+
+// ParseError lists the syntax errors of a program that was not run.
+type ParseError struct {
+	Errors []string
+}
+
+func (e *ParseError) Error() string {
+	return strings.Join(e.Errors, "\n")
+}
+
+// RuntimeError is an error raised while running a program.
+type RuntimeError struct {
+	Err *object.Error
+}
+
+func (e *RuntimeError) Error() string {
+	return e.Err.Inspect()
+}
+
+// Traceback lists the calls leading to the error.
+func (e *RuntimeError) Traceback() string {
+	return e.Err.Traceback()
+}
+
+// ReportError writes an error returned by Run, RunFile or Call to the
+// interpreter's stderr, with the traceback of runtime errors.
+func (in *Interpreter) ReportError(err error) {
+	w := in.env.Runtime.Stderr
+	switch err := err.(type) {
+	case *ParseError:
+		fmt.Fprintf(w, "Got %d parser errors:\n", len(err.Errors))
+		for _, e := range err.Errors {
+			fmt.Fprintln(w, e)
+		}
+	case *RuntimeError:
+		fmt.Fprintln(w, err.Traceback())
+	default:
+		fmt.Fprintln(w, err)
+	}
+}
+
+// New creates an interpreter with its own copy of the default builtins.
+func New() *Interpreter {
+	env := object.NewEnvironment(nil)
+	env.Runtime.Builtins = evaluator.Builtins.Copy()
+	return &Interpreter{env: env}
+}
+
+// SetStdout sets where 講 prints to, os.Stdout by default.
+func (in *Interpreter) SetStdout(w io.Writer) {
+	in.env.Runtime.Stdout = w
+}
+
+// SetStderr sets where errors are reported to, os.Stderr by default.
+func (in *Interpreter) SetStderr(w io.Writer) {
+	in.env.Runtime.Stderr = w
+}
+
+// SetStdin sets where input is read from, os.Stdin by default.
+func (in *Interpreter) SetStdin(r io.Reader) {
+	in.env.Runtime.Stdin = r
+}
+
+// SetSearchPath sets the directories 引入 looks in after the importing file's.
+func (in *Interpreter) SetSearchPath(dirs ...string) {
+	in.env.Runtime.SearchPath = dirs
+}
+
+// Run runs source and returns the value of its last statement.
+func (in *Interpreter) Run(source string) (object.Object, error) {
+	return in.run("", source)
+}
+
+// RunFile runs the file at path, imports are relative to it.
+func (in *Interpreter) RunFile(path string) (object.Object, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	// an import cycle back to this file is an error, not a second run of it
+	rt := in.env.Runtime
+	rt.Loading = append(rt.Loading, abs)
+	defer func() {
+		rt.Loading = rt.Loading[:len(rt.Loading)-1]
+	}()
+	return in.run(path, string(data))
+}
+
+func (in *Interpreter) run(filename string, source string) (object.Object, error) {
+	p := parser.New(lexer.NewFile(filename, source))
+	program := p.ParseProgram()
+	if len(p.Errors) > 0 {
+		return nil, &ParseError{Errors: p.Errors}
+	}
+	return result(evaluator.Eval(program, in.env))
+}
+
+// Call calls the function or builtin named fnName with args converted by
+// ToObject.
+func (in *Interpreter) Call(fnName string, args ...interface{}) (object.Object, error) {
+	fn, ok := in.env.Get(fnName)
+	if !ok {
+		builtin, ok := in.env.Runtime.Builtins.Get(fnName)
+		if !ok {
+			return nil, fmt.Errorf("cantolang: undefined function %s", fnName)
+		}
+		fn = builtin
+	}
+	objs := make([]object.Object, len(args))
+	for i, arg := range args {
+		obj, err := ToObject(arg)
+		if err != nil {
+			return nil, err
+		}
+		objs[i] = obj
+	}
+	return result(evaluator.ApplyFunction(fn, objs, in.env.Runtime))
+}
+
+// Register adds a builtin for the programs this interpreter runs, replacing
+// any builtin with the same name. fn is called with between minArgs and
+// maxArgs arguments, object.VARIADIC allows any number.
+func (in *Interpreter) Register(name string, minArgs int, maxArgs int, doc string, fn object.BuiltInFunction) {
+	in.env.Runtime.Builtins.Register(&object.BuiltIn{Name: name, MinArgs: minArgs, MaxArgs: maxArgs, Doc: doc, Fn: fn})
+}
+
+// Unregister removes a builtin from the programs this interpreter runs.
+func (in *Interpreter) Unregister(name string) {
+	in.env.Runtime.Builtins.Remove(name)
+}
+
+// Builtins returns the builtins of this interpreter.
+func (in *Interpreter) Builtins() *object.Registry {
+	return in.env.Runtime.Builtins
+}
+
+// Set sets the global variable name to value converted by ToObject.
+func (in *Interpreter) Set(name string, value interface{}) error {
+	obj, err := ToObject(value)
+	if err != nil {
+		return err
+	}
+	in.env.Set(name, obj)
+	return nil
+}
+
+// Get returns the global variable name.
+func (in *Interpreter) Get(name string) (object.Object, bool) {
+	return in.env.Get(name)
+}
+
+func result(obj object.Object) (object.Object, error) {
+	if err, ok := obj.(*object.Error); ok {
+		return nil, &RuntimeError{Err: err}
+	}
+	return obj, nil
+}
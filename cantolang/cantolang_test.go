@@ -0,0 +1,304 @@
+package cantolang
+
+import (
+	"bytes"
+	"cantolang/object"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	in := New()
+	out := &bytes.Buffer{}
+	in.SetStdout(out)
+	result, err := in.Run(`塞 2 入 x。 講（x + 1）。 x * 10`)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if FromObject(result) != 20 {
+		t.Errorf("expected 20 got %s", result.Inspect())
+	}
+	if out.String() != "3\n" {
+		t.Errorf("expected output 3 got %q", out.String())
+	}
+
+	// globals stay between runs
+	result, err = in.Run(`x + 1`)
+	if err != nil || FromObject(result) != 3 {
+		t.Errorf("expected 3 got %v %v", result, err)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	in := New()
+	_, err := in.Run(`塞 1 入`)
+	if _, ok := err.(*ParseError); !ok {
+		t.Errorf("expected ParseError got %T", err)
+	}
+
+	_, err = in.Run(`
+	聽到 f（） 嘅話，就「 俾我 1 / 0。 」
+	f（）`)
+	runtimeErr, ok := err.(*RuntimeError)
+	if !ok {
+		t.Fatalf("expected RuntimeError got %T", err)
+	}
+	if runtimeErr.Err.Message != "division by zero" {
+		t.Errorf("expected division by zero got %s", runtimeErr.Err.Message)
+	}
+	if !strings.Contains(runtimeErr.Traceback(), "叫 call f") {
+		t.Errorf("expected traceback through f got %s", runtimeErr.Traceback())
+	}
+
+	_, err = in.RunFile("missing.txt")
+	if err == nil {
+		t.Errorf("expected error for missing file")
+	}
+}
+
+func TestReportError(t *testing.T) {
+	in := New()
+	stderr := &bytes.Buffer{}
+	in.SetStderr(stderr)
+	_, err := in.Run(`講（1，）`)
+	in.ReportError(err)
+	_, err = in.Run(`聽到 f（） 嘅話，就「 俾我 1 / 0。 」
+f（）`)
+	in.ReportError(err)
+	expected := `Got 1 parser errors:
+1:5: invalid token ）(CLOSE_PAREN)
+追蹤（最近嘅呼叫喺最尾）traceback (most recent call last):
+  2:1 叫 call f
+1:17: *object.Integer (&{Value:1}) / *object.Integer (&{Value:0}): division by zero
+`
+	if stderr.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, stderr.String())
+	}
+}
+
+func TestRunFileImportCycle(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt": `講（“a runs”）。 引入 “b”。`,
+		"b.txt": `講（“b runs”）。 引入 “a”。`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	in := New()
+	out := &bytes.Buffer{}
+	in.SetStdout(out)
+	_, err := in.RunFile(filepath.Join(dir, "a.txt"))
+	runtimeErr, ok := err.(*RuntimeError)
+	if !ok {
+		t.Fatalf("expected RuntimeError got %T (%v)", err, err)
+	}
+	if runtimeErr.Err.Description != "import cycle a.txt -> b.txt -> a.txt" {
+		t.Errorf("expected cycle back to a.txt got %q", runtimeErr.Err.Description)
+	}
+	if out.String() != "a runs\nb runs\n" {
+		t.Errorf("expected each file to run once got %q", out.String())
+	}
+	if len(in.env.Runtime.Loading) != 0 {
+		t.Errorf("expected no files loading after the run got %v", in.env.Runtime.Loading)
+	}
+}
+
+func TestCall(t *testing.T) {
+	in := New()
+	_, err := in.Run(`
+	聽到 打招呼（x） 嘅話，就「 俾我 “你好，” + x。 」
+	聽到 total（arr） 嘅話，就「
+	    塞 0 入 sum。
+	    逐個 x 喺 arr 度，就「 塞 sum + x 入 sum。 」
+	    俾我 sum。
+	」`)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	tests := []struct {
+		fn       string
+		args     []interface{}
+		expected interface{}
+	}{
+		{"打招呼", []interface{}{"世界"}, "你好，世界"},
+		{"total", []interface{}{[]int{1, 2, 3}}, 6},
+		{"有幾長", []interface{}{map[string]int{"a": 1, "b": 2}}, 2},
+		{"加上", []interface{}{[]string{"a"}, "b"}, []interface{}{"a", "b"}},
+	}
+	for _, test := range tests {
+		result, err := in.Call(test.fn, test.args...)
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.fn, err)
+			continue
+		}
+		if got := FromObject(result); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: expected %v got %v", test.fn, test.expected, got)
+		}
+	}
+
+	if _, err := in.Call("missing"); err == nil {
+		t.Errorf("expected error for undefined function")
+	}
+	if _, err := in.Call("打招呼"); err == nil {
+		t.Errorf("expected error for wrong number of arguments")
+	}
+	if _, err := in.Call("打招呼", make(chan int)); err == nil {
+		t.Errorf("expected error for unconvertible argument")
+	}
+}
+
+func TestSetGet(t *testing.T) {
+	in := New()
+	if err := in.Set("名", "阿明"); err != nil {
+		t.Fatal(err)
+	}
+	if err := in.Set("分數", map[string]interface{}{"數學": 90, "英文": 85.5}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := in.Run(`名 + “：” + 中文數字（分數【“數學”】）`)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if FromObject(result) != "阿明：九十" {
+		t.Errorf("expected 阿明：九十 got %s", result.Inspect())
+	}
+
+	in.Run(`塞 名 + “仔” 入 名。`)
+	name, ok := in.Get("名")
+	if !ok || FromObject(name) != "阿明仔" {
+		t.Errorf("expected 阿明仔 got %v", name)
+	}
+	if _, ok := in.Get("missing"); ok {
+		t.Errorf("expected missing to be unset")
+	}
+	if err := in.Set("x", struct{}{}); err == nil {
+		t.Errorf("expected error for struct value")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, "NULL"},
+		{true, "true"},
+		{int64(-3), "-3"},
+		{uint8(7), "7"},
+		{1.5, "1.5"},
+		{"你好", "你好"},
+		{[]interface{}{1, "a", false}, "[1, a, false]"},
+		{[2]int{1, 2}, "[1, 2]"},
+		{map[string]int{"b": 2, "a": 1}, "{a: 1, b: 2}"},
+		{map[int][]string{1: {"x"}}, "{1: [x]}"},
+		{&object.Integer{Value: 4}, "4"},
+	}
+	for _, test := range tests {
+		obj, err := ToObject(test.value)
+		if err != nil {
+			t.Errorf("%v: unexpected error %s", test.value, err)
+			continue
+		}
+		if obj.Inspect() != test.expected {
+			t.Errorf("%v: expected %s got %s", test.value, test.expected, obj.Inspect())
+		}
+	}
+
+	in := New()
+	result, err := in.Run(`{“a”: [1, 2.5, 啱], 3: “三”}`)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expected := map[string]interface{}{"a": []interface{}{1, 2.5, true}, "3": "三"}
+	if got := FromObject(result); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v got %v", expected, got)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	in := New()
+	in.Register("問候", 0, 1, "問候（name）greet someone", func(rt *object.Runtime, args ...object.Object) object.Object {
+		if len(args) == 0 {
+			return &object.String{Value: "你好"}
+		}
+		return &object.String{Value: "你好，" + args[0].Inspect()}
+	})
+	in.Register("有幾長", 1, 1, "always 0", func(rt *object.Runtime, args ...object.Object) object.Object {
+		return &object.Integer{Value: 0}
+	})
+	in.Unregister("中文數字")
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`問候（）`, "你好"},
+		{`問候（“阿明”）`, "你好，阿明"},
+		{`說明（問候）`, "問候（name）greet someone"},
+		{`有幾長（[1, 2]）`, "0"},
+		{`中文數字`, "NULL"},
+	}
+	for _, test := range tests {
+		result, err := in.Run(test.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.input, err)
+			continue
+		}
+		if result.Inspect() != test.expected {
+			t.Errorf("%s: expected %s got %s", test.input, test.expected, result.Inspect())
+		}
+	}
+
+	// aliases follow the builtin they name
+	in.Register("大楷", 1, 1, "shout", func(rt *object.Runtime, args ...object.Object) object.Object {
+		return &object.String{Value: args[0].Inspect() + "！"}
+	})
+	if result, err := in.Run(`upper（“hi”）`); err != nil || result.Inspect() != "hi！" {
+		t.Errorf("expected upper to call the new 大楷 got %v %v", result, err)
+	}
+	in.Unregister("分割")
+	if _, ok := in.Builtins().Get("split"); ok {
+		t.Errorf("expected split to go with 分割")
+	}
+	if result, _ := New().Run(`split（“a b”）`); result.Inspect() != "[a, b]" {
+		t.Errorf("expected split in other interpreters got %s", result.Inspect())
+	}
+
+	if _, err := in.Run(`問候（1，2）`); err == nil {
+		t.Errorf("expected wrong number of arguments")
+	}
+	if result, err := in.Call("問候", "世界"); err != nil || FromObject(result) != "你好，世界" {
+		t.Errorf("expected 你好，世界 got %v %v", result, err)
+	}
+
+	// other interpreters keep the defaults
+	other := New()
+	if result, _ := other.Run(`有幾長（[1, 2]）`); result.Inspect() != "2" {
+		t.Errorf("expected 2 got %s", result.Inspect())
+	}
+	if _, ok := other.Builtins().Get("問候"); ok {
+		t.Errorf("expected 問候 only in the first interpreter")
+	}
+}
+
+func TestStdin(t *testing.T) {
+	in := New()
+	in.SetStdin(strings.NewReader("3\n4\n"))
+	result, err := in.Run(`轉數字（聽（）） * 轉數字（聽（））`)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if FromObject(result) != 12 {
+		t.Errorf("expected 12 got %s", result.Inspect())
+	}
+	if result, _ := in.Run(`聽（）`); result != object.NULL {
+		t.Errorf("expected NULL at end of input got %s", result.Inspect())
+	}
+}
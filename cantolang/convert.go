@@ -0,0 +1,117 @@
+package cantolang
+
+import (
+	"cantolang/object"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ToObject converts a Go value to a Cantolang value. nil, bools, integers,
+// floats, strings, slices, arrays and maps with string, integer or bool keys
+// are supported, object.Object values are returned as they are.
+func ToObject(value interface{}) (object.Object, error) {
+	if value == nil {
+		return object.NULL, nil
+	}
+	if obj, ok := value.(object.Object); ok {
+		return obj, nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return object.TRUE, nil
+		}
+		return object.FALSE, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &object.Integer{Value: int(v.Int())}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &object.Integer{Value: int(v.Uint())}, nil
+	case reflect.Float32, reflect.Float64:
+		return &object.Float{Value: v.Float()}, nil
+	case reflect.String:
+		return &object.String{Value: v.String()}, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return object.NULL, nil
+		}
+		items := make([]object.Object, v.Len())
+		for i := range items {
+			item, err := ToObject(v.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return &object.Array{Items: items}, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return object.NULL, nil
+		}
+		// sort the keys so the hash keeps the same order every time
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		hash := object.NewHash()
+		for _, k := range keys {
+			key, err := ToObject(k.Interface())
+			if err != nil {
+				return nil, err
+			}
+			hashable, ok := key.(object.Hashable)
+			if !ok {
+				return nil, fmt.Errorf("cantolang: unusable as hash key: %T", k.Interface())
+			}
+			val, err := ToObject(v.MapIndex(k).Interface())
+			if err != nil {
+				return nil, err
+			}
+			hash.Set(hashable, val)
+		}
+		return hash, nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return object.NULL, nil
+		}
+		return ToObject(v.Elem().Interface())
+	}
+	return nil, fmt.Errorf("cantolang: cannot convert %T", value)
+}
+
+// FromObject converts a Cantolang value to a Go value: int, float64, string,
+// bool, nil, []interface{} or map[string]interface{} with non string keys
+// written with Inspect. Other values, like functions, are returned as they are.
+func FromObject(obj object.Object) interface{} {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return obj.Value
+	case *object.Float:
+		return obj.Value
+	case *object.String:
+		return obj.Value
+	case *object.Boolean:
+		return obj.Value
+	case *object.Null:
+		return nil
+	case *object.Array:
+		items := make([]interface{}, len(obj.Items))
+		for i, item := range obj.Items {
+			items[i] = FromObject(item)
+		}
+		return items
+	case *object.Hash:
+		m := make(map[string]interface{}, len(obj.Keys))
+		for _, k := range obj.Keys {
+			pair := obj.Pairs[k]
+			key := pair.Key.Inspect()
+			if s, ok := pair.Key.(*object.String); ok {
+				key = s.Value
+			}
+			m[key] = FromObject(pair.Value)
+		}
+		return m
+	}
+	return obj
+}
@@ -0,0 +1,300 @@
+package evaluator
+
+import (
+	"cantolang/object"
+	"cantolang/token"
+	"sort"
+)
+
+// arrayBuiltins return new arrays rather than changing the ones they are
+// given. Callbacks are called like any other function, so they can be user
+// functions or builtins.
+var arrayBuiltins = map[string]*object.BuiltIn{
+	"變晒": {
+		MinArgs: 2,
+		MaxArgs: 2,
+		Doc:     "變晒（arr，fn）array of fn（item） for each item",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			arr, err := arrayArg(args[0])
+			if err != nil {
+				return err
+			}
+			if err := functionArg(args[1]); err != nil {
+				return err
+			}
+			items := make([]object.Object, len(arr.Items))
+			for i, item := range arr.Items {
+				result := applyCallback(args[1], []object.Object{item}, rt)
+				if isError(result) {
+					return result
+				}
+				items[i] = result
+			}
+			return &object.Array{Items: items}
+		},
+	},
+	"揀": {
+		MinArgs: 2,
+		MaxArgs: 2,
+		Doc:     "揀（arr，fn）array of the items where fn（item） is true",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			arr, err := arrayArg(args[0])
+			if err != nil {
+				return err
+			}
+			if err := functionArg(args[1]); err != nil {
+				return err
+			}
+			items := []object.Object{}
+			for _, item := range arr.Items {
+				result := applyCallback(args[1], []object.Object{item}, rt)
+				if isError(result) {
+					return result
+				}
+				if isTruthy(result) {
+					items = append(items, item)
+				}
+			}
+			return &object.Array{Items: items}
+		},
+	},
+	"累計": {
+		MinArgs: 2,
+		MaxArgs: 3,
+		Doc:     "累計（arr，fn，start）fn（total，item） for each item, starting from start or the first item",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			arr, err := arrayArg(args[0])
+			if err != nil {
+				return err
+			}
+			if err := functionArg(args[1]); err != nil {
+				return err
+			}
+			items := arr.Items
+			var total object.Object
+			if len(args) == 3 {
+				total = args[2]
+			} else {
+				if len(items) == 0 {
+					return Errorf("invalid argument", "累計 of an empty array needs a start value")
+				}
+				total, items = items[0], items[1:]
+			}
+			for _, item := range items {
+				total = applyCallback(args[1], []object.Object{total, item}, rt)
+				if isError(total) {
+					return total
+				}
+			}
+			return total
+		},
+	},
+	"搵": {
+		MinArgs: 2,
+		MaxArgs: 2,
+		Doc:     "搵（arr，fn）the first item where fn（item） is true, NULL if there is none",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			arr, err := arrayArg(args[0])
+			if err != nil {
+				return err
+			}
+			if err := functionArg(args[1]); err != nil {
+				return err
+			}
+			for _, item := range arr.Items {
+				result := applyCallback(args[1], []object.Object{item}, rt)
+				if isError(result) {
+					return result
+				}
+				if isTruthy(result) {
+					return item
+				}
+			}
+			return object.NULL
+		},
+	},
+	"任何": {
+		MinArgs: 2,
+		MaxArgs: 2,
+		Doc:     "任何（arr，fn）whether fn（item） is true for any item",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return testItems(rt, args, true)
+		},
+	},
+	"全部": {
+		MinArgs: 2,
+		MaxArgs: 2,
+		Doc:     "全部（arr，fn）whether fn（item） is true for every item",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return testItems(rt, args, false)
+		},
+	},
+	"排序": {
+		MinArgs: 1,
+		MaxArgs: 2,
+		Doc:     "排序（arr，fn）sorted array, smallest first, or where fn（a，b） is true when a goes before b",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			arr, err := arrayArg(args[0])
+			if err != nil {
+				return err
+			}
+			less := func(a object.Object, b object.Object) object.Object {
+				return evalInfixExpression(a, b, token.Token{TokenType: token.LESS_THAN, TokenLiteral: "細過"})
+			}
+			if len(args) == 2 {
+				if err := functionArg(args[1]); err != nil {
+					return err
+				}
+				less = func(a object.Object, b object.Object) object.Object {
+					return applyCallback(args[1], []object.Object{a, b}, rt)
+				}
+			}
+			items := make([]object.Object, len(arr.Items))
+			copy(items, arr.Items)
+			var sortErr object.Object
+			sort.SliceStable(items, func(i, j int) bool {
+				if sortErr != nil {
+					return false
+				}
+				result := less(items[i], items[j])
+				if isError(result) {
+					sortErr = result
+					return false
+				}
+				return isTruthy(result)
+			})
+			if sortErr != nil {
+				return sortErr
+			}
+			return &object.Array{Items: items}
+		},
+	},
+	"倒轉": {
+		MinArgs: 1,
+		MaxArgs: 1,
+		Doc:     "倒轉（arr）array with the items in reverse order",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			arr, err := arrayArg(args[0])
+			if err != nil {
+				return err
+			}
+			items := make([]object.Object, len(arr.Items))
+			for i, item := range arr.Items {
+				items[len(items)-1-i] = item
+			}
+			return &object.Array{Items: items}
+		},
+	},
+	"切": {
+		MinArgs: 2,
+		MaxArgs: 3,
+		Doc:     "切（x，start，end）items of an array or characters of a string from start up to end, or to the end without end",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			switch arg := args[0].(type) {
+			case *object.Array:
+				start, end, err := sliceBounds(len(arg.Items), args[1:]...)
+				if err != nil {
+					return err
+				}
+				items := make([]object.Object, end-start)
+				copy(items, arg.Items[start:end])
+				return &object.Array{Items: items}
+			case *object.String:
+				runes := arg.Runes()
+				start, end, err := sliceBounds(len(runes), args[1:]...)
+				if err != nil {
+					return err
+				}
+				return &object.String{Value: string(runes[start:end])}
+			}
+			return Errorf("invalid argument type", "expected %s or %s got %s", object.ARRAY_OBJ, object.STRING_OBJ, args[0].Type())
+		},
+	},
+	"駁埋": {
+		MinArgs: 1,
+		MaxArgs: object.VARIADIC,
+		Doc:     "駁埋（arr，…）array of the items of each array in turn",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			items := []object.Object{}
+			for _, arg := range args {
+				arr, err := arrayArg(arg)
+				if err != nil {
+					return err
+				}
+				items = append(items, arr.Items...)
+			}
+			return &object.Array{Items: items}
+		},
+	},
+}
+
+// arrayAliases are English names for the array builtins
+var arrayAliases = map[string]string{
+	"map":     "變晒",
+	"filter":  "揀",
+	"reduce":  "累計",
+	"find":    "搵",
+	"any":     "任何",
+	"all":     "全部",
+	"sort":    "排序",
+	"reverse": "倒轉",
+	"slice":   "切",
+	"concat":  "駁埋",
+}
+
+// testItems reports whether fn（item） is true for any item, or for every
+// item when some is false
+func testItems(rt *object.Runtime, args []object.Object, some bool) object.Object {
+	arr, err := arrayArg(args[0])
+	if err != nil {
+		return err
+	}
+	if err := functionArg(args[1]); err != nil {
+		return err
+	}
+	for _, item := range arr.Items {
+		result := applyCallback(args[1], []object.Object{item}, rt)
+		if isError(result) {
+			return result
+		}
+		if isTruthy(result) == some {
+			return getBoolObj(some)
+		}
+	}
+	return getBoolObj(!some)
+}
+
+func arrayArg(arg object.Object) (*object.Array, *object.Error) {
+	arr, ok := arg.(*object.Array)
+	if !ok {
+		return nil, Errorf("invalid argument type", "expected %s got %s", object.ARRAY_OBJ, arg.Type())
+	}
+	return arr, nil
+}
+
+// functionArg checks that a callback can be called
+func functionArg(arg object.Object) *object.Error {
+	switch arg.(type) {
+	case *object.Function, *object.BuiltIn:
+		return nil
+	}
+	return Errorf("invalid argument type", "expected %s got %s", object.FUNCTION_OBJ, arg.Type())
+}
+
+// sliceBounds checks the start and optional end of a slice of something of
+// length n, the end defaults to n
+func sliceBounds(n int, args ...object.Object) (int, int, *object.Error) {
+	bounds := []int{0, n}
+	for i, arg := range args {
+		num, ok := arg.(*object.Integer)
+		if !ok {
+			return 0, 0, Errorf("invalid argument type", "expected %s got %s", object.INT_OBJ, arg.Type())
+		}
+		bounds[i] = num.Value
+	}
+	start, end := bounds[0], bounds[1]
+	if start < 0 || end > n || start > end {
+		return 0, 0, Errorf("index error", "slice %d to %d out of range for length %d", start, end, n)
+	}
+	return start, end, nil
+}
@@ -1,48 +1,308 @@
 package evaluator
 
 import (
+	"bufio"
 	"bytes"
+	"cantolang/numeral"
 	"cantolang/object"
 	"fmt"
+	"io"
+	"math"
+	"strings"
 )
 
-var Builtins = map[string]object.BuiltInFunction{
-	"有幾長": func(args ...object.Object) object.Object {
-		if len(args) != 1 {
-			return Errorf("wrong number of arguments", "expected 1 arg got %d", len(args))
-		}
-		switch arg := args[0].(type) {
-		case *object.Array:
-			return &object.Integer{Value: len(arg.Items)}
-		case *object.String:
-			return &object.Integer{Value: len(arg.Value)}
-		}
-		return Errorf("invalid argument type", "%s", args[0].Type())
+// Builtins are the default builtins, used by runtimes without their own
+// registry.
+var Builtins = object.NewRegistry(map[string]*object.BuiltIn{
+	"有幾長": {
+		MinArgs: 1,
+		MaxArgs: 1,
+		Doc:     "有幾長（x）length of a string, array, hash or range",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			switch arg := args[0].(type) {
+			case *object.Array:
+				return &object.Integer{Value: len(arg.Items)}
+			case *object.String:
+				return &object.Integer{Value: arg.Len()}
+			case *object.Hash:
+				return &object.Integer{Value: len(arg.Keys)}
+			case *object.Range:
+				return &object.Integer{Value: arg.Len()}
+			}
+			return Errorf("invalid argument type", "%s", args[0].Type())
+		},
 	},
-	"講": func(args ...object.Object) object.Object {
-		if len(args) == 0 {
-			return Errorf("wrong number of arguments", "expected 1 or more got 0")
-		}
-		buff := bytes.Buffer{}
-		for _, arg := range args {
-			buff.WriteString(arg.Inspect() + " ")
-		}
-		fmt.Println(buff.String())
-		return object.NULL
+	"講": {
+		MinArgs: 1,
+		MaxArgs: object.VARIADIC,
+		Doc:     "講（x，…）print values",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			buff := bytes.Buffer{}
+			for i, arg := range args {
+				if i != 0 {
+					buff.WriteString(" ")
+				}
+				buff.WriteString(arg.Inspect())
+			}
+			fmt.Fprintln(rt.Stdout, buff.String())
+			return object.NULL
+		},
 	},
-	"加上": func(args ...object.Object) object.Object {
-		if len(args) < 2 {
-			return Errorf("wrong number of arguments", "expected 2 or more got 0")
-		}
-		if args[0].Type() != object.ARRAY_OBJ {
-			return Errorf("invalid argument type", "expected array got %s", args[0].Type())
+	"聽": {
+		MinArgs: 0,
+		MaxArgs: 1,
+		Doc:     "聽（prompt）read a line of input, NULL at the end of input",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) == 1 {
+				fmt.Fprint(rt.Stdout, args[0].Inspect())
+			}
+			line, err := stdin(rt).ReadString('\n')
+			if err != nil && err != io.EOF {
+				return Errorf("io error", "%s", err)
+			}
+			if err == io.EOF && line == "" {
+				return object.NULL
+			}
+			line = strings.TrimSuffix(line, "\n")
+			return &object.String{Value: strings.TrimSuffix(line, "\r")}
+		},
+	},
+	"聽晒": {
+		MinArgs: 0,
+		MaxArgs: 0,
+		Doc:     "聽晒（）read the rest of the input, NULL at the end of input",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			data, err := io.ReadAll(stdin(rt))
+			if err != nil {
+				return Errorf("io error", "%s", err)
+			}
+			if len(data) == 0 {
+				return object.NULL
+			}
+			return &object.String{Value: string(data)}
+		},
+	},
+	"轉數字": {
+		MinArgs: 1,
+		MaxArgs: 1,
+		Doc:     "轉數字（s）convert a string like “12”、“－１２” or “十二” to an integer",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			switch arg := args[0].(type) {
+			case *object.Integer:
+				return arg
+			case *object.String:
+				s := strings.TrimSpace(arg.Value)
+				sign := 1
+				for _, prefix := range []string{"-", "－", "負"} {
+					if strings.HasPrefix(s, prefix) {
+						s = strings.TrimPrefix(s, prefix)
+						sign = -1
+						break
+					}
+				}
+				n, err := numeral.Parse(s)
+				if err != nil {
+					return Errorf("invalid argument", "cannot convert %q to number", arg.Value)
+				}
+				return &object.Integer{Value: sign * n}
+			}
+			return Errorf("invalid argument type", "expected %s got %s", object.STRING_OBJ, args[0].Type())
+		},
+	},
+	"加上": {
+		MinArgs: 2,
+		MaxArgs: object.VARIADIC,
+		Doc:     "加上（arr，x，…）new array with values added to the end",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if args[0].Type() != object.ARRAY_OBJ {
+				return Errorf("invalid argument type", "expected array got %s", args[0].Type())
+			}
+			oldArr := args[0].(*object.Array).Items
+			newArr := make([]object.Object, len(oldArr))
+			copy(newArr, oldArr)
+			for _, arg := range args[1:] {
+				newArr = append(newArr, arg)
+			}
+			return &object.Array{Items: newArr}
+		},
+	},
+	"所有鍵": {
+		MinArgs: 1,
+		MaxArgs: 1,
+		Doc:     "所有鍵（hash）array of the keys of a hash",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			hash, ok := args[0].(*object.Hash)
+			if !ok {
+				return Errorf("invalid argument type", "expected %s got %s", object.HASH_OBJ, args[0].Type())
+			}
+			keys := []object.Object{}
+			for _, k := range hash.Keys {
+				keys = append(keys, hash.Pairs[k].Key)
+			}
+			return &object.Array{Items: keys}
+		},
+	},
+	"所有值": {
+		MinArgs: 1,
+		MaxArgs: 1,
+		Doc:     "所有值（hash）array of the values of a hash",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			hash, ok := args[0].(*object.Hash)
+			if !ok {
+				return Errorf("invalid argument type", "expected %s got %s", object.HASH_OBJ, args[0].Type())
+			}
+			values := []object.Object{}
+			for _, k := range hash.Keys {
+				values = append(values, hash.Pairs[k].Value)
+			}
+			return &object.Array{Items: values}
+		},
+	},
+	"有冇": {
+		MinArgs: 2,
+		MaxArgs: 2,
+		Doc:     "有冇（hash，key）whether a hash has a key",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			hash, ok := args[0].(*object.Hash)
+			if !ok {
+				return Errorf("invalid argument type", "expected %s got %s", object.HASH_OBJ, args[0].Type())
+			}
+			key, ok := args[1].(object.Hashable)
+			if !ok {
+				return Errorf("type error", "unusable as hash key: %s", args[1].Type())
+			}
+			_, found := hash.Get(key)
+			return getBoolObj(found)
+		},
+	},
+	"刪除": {
+		MinArgs: 2,
+		MaxArgs: 2,
+		Doc:     "刪除（hash，key）new hash without a key",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			hash, ok := args[0].(*object.Hash)
+			if !ok {
+				return Errorf("invalid argument type", "expected %s got %s", object.HASH_OBJ, args[0].Type())
+			}
+			key, ok := args[1].(object.Hashable)
+			if !ok {
+				return Errorf("type error", "unusable as hash key: %s", args[1].Type())
+			}
+			newHash := hash.Copy()
+			newHash.Delete(key)
+			return newHash
+		},
+	},
+	"範圍": {
+		MinArgs: 1,
+		MaxArgs: 3,
+		Doc:     "範圍（end）、範圍（start，end）or 範圍（start，end，step）integers from start up to end",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			nums := []int{}
+			for _, arg := range args {
+				n, ok := arg.(*object.Integer)
+				if !ok {
+					return Errorf("invalid argument type", "expected %s got %s", object.INT_OBJ, arg.Type())
+				}
+				nums = append(nums, n.Value)
+			}
+			r := &object.Range{Start: 0, End: nums[0], Step: 1}
+			switch len(nums) {
+			case 2:
+				r.Start, r.End = nums[0], nums[1]
+			case 3:
+				if nums[2] == 0 {
+					return Errorf("invalid argument", "range step cannot be 0")
+				}
+				r.Start, r.End, r.Step = nums[0], nums[1], nums[2]
+			}
+			if r.Len64() > math.MaxInt {
+				return Errorf("invalid argument", "range from %d to %d is too long", r.Start, r.End)
+			}
+			return r
+		},
+	},
+	"掟": {
+		MinArgs: 1,
+		MaxArgs: 2,
+		Doc:     "掟（message，description）raise an error, or 掟（e）raise a caught error again",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if errVal, ok := args[0].(*object.ErrorValue); ok && len(args) == 1 {
+				// raise a caught error again
+				err := *errVal.Error
+				err.Stack = append([]object.Frame{}, err.Stack...)
+				return &err
+			}
+			message, ok := args[0].(*object.String)
+			if !ok {
+				return Errorf("invalid argument type", "expected %s got %s", object.STRING_OBJ, args[0].Type())
+			}
+			description := ""
+			if len(args) == 2 {
+				desc, ok := args[1].(*object.String)
+				if !ok {
+					return Errorf("invalid argument type", "expected %s got %s", object.STRING_OBJ, args[1].Type())
+				}
+				description = desc.Value
+			}
+			return Errorf(message.Value, "%s", description)
+		},
+	},
+	"中文數字": {
+		MinArgs: 1,
+		MaxArgs: 1,
+		Doc:     "中文數字（n）write an integer in Chinese numerals",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			n, ok := args[0].(*object.Integer)
+			if !ok {
+				return Errorf("invalid argument type", "expected %s got %s", object.INT_OBJ, args[0].Type())
+			}
+			return &object.String{Value: numeral.Format(n.Value)}
+		},
+	},
+	"說明": {
+		MinArgs: 1,
+		MaxArgs: 1,
+		Doc:     "說明（builtin）documentation of a builtin",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			b, ok := args[0].(*object.BuiltIn)
+			if !ok {
+				return Errorf("invalid argument type", "expected %s got %s", object.BUILTIN_OBJ, args[0].Type())
+			}
+			return &object.String{Value: b.Doc}
+		},
+	},
+})
+
+func init() {
+	for _, builtins := range []map[string]*object.BuiltIn{stringBuiltins, arrayBuiltins} {
+		for name, b := range builtins {
+			b.Name = name
+			Builtins.Register(b)
 		}
-		oldArr := args[0].(*object.Array).Items
-		newArr := make([]object.Object, len(oldArr))
-		copy(newArr, oldArr)
-		for _, arg := range args[1:] {
-			newArr = append(newArr, arg)
+	}
+	for _, aliases := range []map[string]string{stringAliases, arrayAliases} {
+		for alias, name := range aliases {
+			Builtins.Alias(alias, name)
 		}
-		return &object.Array{Items: newArr}
-	},
+	}
+}
+
+// stdin returns a buffered reader for rt.Stdin, keeping it so input read
+// ahead by one call is there for the next
+func stdin(rt *object.Runtime) *bufio.Reader {
+	if r, ok := rt.Stdin.(*bufio.Reader); ok {
+		return r
+	}
+	r := bufio.NewReader(rt.Stdin)
+	rt.Stdin = r
+	return r
+}
+
+// runtimeBuiltins returns the builtins of rt, the defaults unless it has its own
+func runtimeBuiltins(rt *object.Runtime) *object.Registry {
+	if rt != nil && rt.Builtins != nil {
+		return rt.Builtins
+	}
+	return Builtins
 }
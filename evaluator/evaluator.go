@@ -5,13 +5,21 @@ import (
 	"cantolang/object"
 	"cantolang/token"
 	"fmt"
+	"strings"
 )
 
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	result := eval(node, env)
+	// tag errors with the innermost node they were raised from
+	if err, ok := result.(*object.Error); ok && !err.Pos.IsValid() {
+		err.Pos = node.Pos()
+	}
+	return result
+}
+
+func eval(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
-		object.ERROR.Message = ""
-		object.ERROR.Description = ""
 		return EvalStatements(node.Statements, env, true)
 	case *ast.ExpressionStatement:
 		return Eval(node.Expression, env)
@@ -21,8 +29,18 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return EvalAssignStatement(node, env)
 	case *ast.FunctionDefStatment:
 		return EvalFunctionDefStatement(node, env)
+	case *ast.ImportStatement:
+		return EvalImportStatement(node, env)
 	case *ast.ReturnStatement:
-		return &object.ReturnValue{Value: Eval(node.Expression, env)}
+		val := Eval(node.Expression, env)
+		if isError(val) {
+			return val
+		}
+		return &object.ReturnValue{Value: val}
+	case *ast.BreakStatement:
+		return object.BREAK
+	case *ast.ContinueStatement:
+		return object.CONTINUE
 	case *ast.IncrementDecrementStatement:
 		val, ok := env.Get(node.Identifier)
 		if !ok {
@@ -31,9 +49,16 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		switch val := val.(type) {
 		case *object.Integer:
 			if node.IsIncrement {
-				env.Set(node.Identifier, &object.Integer{Value: val.Value + 1})
+				env.Assign(node.Identifier, &object.Integer{Value: val.Value + 1})
+			} else {
+				env.Assign(node.Identifier, &object.Integer{Value: val.Value - 1})
+			}
+			return object.NULL
+		case *object.Float:
+			if node.IsIncrement {
+				env.Assign(node.Identifier, &object.Float{Value: val.Value + 1})
 			} else {
-				env.Set(node.Identifier, &object.Integer{Value: val.Value - 1})
+				env.Assign(node.Identifier, &object.Float{Value: val.Value - 1})
 			}
 			return object.NULL
 		default:
@@ -45,17 +70,62 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 }
 
 func EvalFunctionDefStatement(statement *ast.FunctionDefStatment, env *object.Environment) object.Object {
-	function := &object.Function{Parameters: statement.Parameters, Body: statement.Body}
-	env.Set(statement.Identifier, function)
+	function := &object.Function{Name: statement.Identifier, Parameters: statement.Parameters, Body: statement.Body, Env: env}
+	env.Scope().Set(statement.Identifier, function)
 	return function
 }
 
 func EvalAssignStatement(statement *ast.AssignStatement, env *object.Environment) object.Object {
 	val := Eval(statement.Expression, env)
-	env.Set(statement.Identifier, val)
+	if isError(val) {
+		return val
+	}
+	switch target := statement.Target.(type) {
+	case *ast.Identifier:
+		env.Assign(target.Token.TokenLiteral, val)
+	case *ast.IndexExpression:
+		left := Eval(target.Left, env)
+		if isError(left) {
+			return left
+		}
+		idx := Eval(target.Index, env)
+		if isError(idx) {
+			return idx
+		}
+		if err := evalIndexAssignment(left, idx, val); err != nil {
+			err.Pos = target.Pos()
+			return err
+		}
+	}
 	return val
 }
 
+// evalIndexAssignment sets an array element or hash value in place
+func evalIndexAssignment(left object.Object, index object.Object, val object.Object) *object.Error {
+	switch left := left.(type) {
+	case *object.Array:
+		idx, ok := index.(*object.Integer)
+		if !ok {
+			return Errorf("type error", "index must be number")
+		}
+		if idx.Value < 0 || idx.Value >= len(left.Items) {
+			return Errorf("index error", "list assignment index out of range")
+		}
+		left.Items[idx.Value] = val
+	case *object.Hash:
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return Errorf("type error", "unusable as hash key: %s", index.Type())
+		}
+		left.Set(key, val)
+	case *object.String:
+		return Errorf("type error", "cannot assign to string index")
+	default:
+		return Errorf("type error", "cannot index %s", left.Type())
+	}
+	return nil
+}
+
 func EvalStatements(statements []ast.Statement, env *object.Environment, unwrapReturn bool) object.Object {
 	var result object.Object
 	result = object.NULL
@@ -67,126 +137,428 @@ func EvalStatements(statements []ast.Statement, env *object.Environment, unwrapR
 			}
 			return result
 		}
-		if object.ERROR.Message != "" {
-			return object.ERROR
+		if isError(result) || result.Type() == object.BREAK_OBJ || result.Type() == object.CONTINUE_OBJ {
+			return result
 		}
 	}
 	return result
 }
 
 func EvalExpression(expression ast.Expression, env *object.Environment) object.Object {
-	if object.ERROR.Message != "" {
-		return object.ERROR
-	}
 	switch expression := expression.(type) {
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: expression.Value}
+	case *ast.FloatLiteral:
+		return &object.Float{Value: expression.Value}
 	case *ast.StringLiteral:
 		return &object.String{Value: expression.Value}
+	case *ast.TemplateLiteral:
+		return evalTemplateLiteral(expression, env)
 	case *ast.ArrayLiteral:
 		arr := &object.Array{}
 		for _, item := range expression.Items {
 			res := Eval(item, env)
-			if object.ERROR.Message != "" {
-				return object.ERROR
+			if isError(res) {
+				return res
 			}
 			arr.Items = append(arr.Items, res)
 		}
 		return arr
-	case *ast.IndexExpression:
-		idxObj := EvalExpression(expression.Index, env)
-		if object.ERROR.Message != "" {
-			return object.ERROR
-		}
-		idx, ok := idxObj.(*object.Integer)
-		if !ok {
-			return Errorf("type error", "index must be number")
+	case *ast.HashLiteral:
+		hash := object.NewHash()
+		for _, pair := range expression.Pairs {
+			key := Eval(pair.Key, env)
+			if isError(key) {
+				return key
+			}
+			hashKey, ok := key.(object.Hashable)
+			if !ok {
+				return Errorf("type error", "unusable as hash key: %s", key.Type())
+			}
+			value := Eval(pair.Value, env)
+			if isError(value) {
+				return value
+			}
+			hash.Set(hashKey, value)
 		}
+		return hash
+	case *ast.IndexExpression:
 		left := Eval(expression.Left, env)
-		if object.ERROR.Message != "" {
-			return object.ERROR
+		if isError(left) {
+			return left
 		}
-		switch left := left.(type) {
-		case *object.Array:
-			if idx.Value < 0 || idx.Value >= len(left.Items) {
-				return Errorf("index error", "list index out of range")
-			}
-			return left.Items[idx.Value]
-		case *object.String:
-			if idx.Value < 0 || idx.Value >= len(left.Value) {
-				return Errorf("index error", "string index out of range")
-			}
-			return &object.String{Value: string([]rune(left.Value)[idx.Value])}
-
-		default:
-			return Errorf("type error", "cannot index %s", left.Type())
+		idx := EvalExpression(expression.Index, env)
+		if isError(idx) {
+			return idx
 		}
+		return evalIndexExpression(left, idx)
 	case *ast.Boolean:
 		return getBoolObj(expression.Value)
 	case *ast.PrefixExpression:
 		right := Eval(expression.Right, env)
+		if isError(right) {
+			return right
+		}
 		return evalPrefixExpression(expression.PrefixToken.TokenType, right)
 	case *ast.InfixExpression:
 		left := Eval(expression.Left, env)
+		if isError(left) {
+			return left
+		}
+		if expression.Infix.TokenType == token.AND || expression.Infix.TokenType == token.OR {
+			return evalLogicalExpression(left, expression, env)
+		}
 		right := Eval(expression.Right, env)
+		if isError(right) {
+			return right
+		}
 		return evalInfixExpression(left, right, expression.Infix)
 	case *ast.IfExpression:
 		condition := Eval(expression.Condition, env)
+		if isError(condition) {
+			return condition
+		}
 		if isTruthy(condition) {
 			return EvalStatements(expression.Consequence.Statements, env, false)
 		}
+		for _, elseIf := range expression.ElseIfs {
+			condition := Eval(elseIf.Condition, env)
+			if isError(condition) {
+				return condition
+			}
+			if isTruthy(condition) {
+				return EvalStatements(elseIf.Consequence.Statements, env, false)
+			}
+		}
 		if expression.Alternative != nil {
 			return EvalStatements(expression.Alternative.Statements, env, false)
 		}
 		return object.NULL
 	case *ast.WhileLoop:
-		condition := Eval(expression.Condition, env)
 		var result object.Object
 		result = object.NULL
-		for isTruthy(condition) {
-			result = EvalStatements(expression.Body.Statements, env, false)
-			if object.ERROR.Message != "" {
-				return object.ERROR
+		for {
+			condition := Eval(expression.Condition, env)
+			if isError(condition) {
+				return condition
 			}
-			if result.Type() == object.RETURN_OBJ {
+			if !isTruthy(condition) {
 				return result
 			}
-			condition = Eval(expression.Condition, env)
+			result = EvalStatements(expression.Body.Statements, env, false)
+			if isError(result) || result.Type() == object.RETURN_OBJ {
+				return result
+			}
+			if result.Type() == object.BREAK_OBJ {
+				return object.NULL
+			}
+			if result.Type() == object.CONTINUE_OBJ {
+				result = object.NULL
+			}
+		}
+	case *ast.ForEachLoop:
+		return evalForEachLoop(expression, env)
+	case *ast.MatchExpression:
+		return evalMatchExpression(expression, env)
+	case *ast.MemberExpression:
+		left := Eval(expression.Object, env)
+		if isError(left) {
+			return left
 		}
-		return result
+		return evalMemberExpression(left, expression.Member.Token.TokenLiteral)
+	case *ast.TryExpression:
+		result := EvalStatements(expression.Body.Statements, env, false)
+		err, ok := result.(*object.Error)
+		if !ok {
+			return result
+		}
+		catchEnv := object.NewBlockEnvironment(env)
+		if expression.ErrorName != nil {
+			catchEnv.Set(expression.ErrorName.Token.TokenLiteral, &object.ErrorValue{Error: err})
+		}
+		return EvalStatements(expression.Catch.Statements, catchEnv, false)
 	case *ast.Identifier:
 		val, ok := env.Get(expression.Token.TokenLiteral)
 		if ok {
 			return val
 		}
+		if builtin, ok := runtimeBuiltins(env.Runtime).Get(expression.Token.TokenLiteral); ok {
+			return builtin
+		}
 		return object.NULL
+	case *ast.FunctionLiteral:
+		return &object.Function{Parameters: expression.Parameters, Body: expression.Body, Env: env}
 	case *ast.FunctionCallExpression:
-		if obj, ok := env.Get(expression.Identifier.Token.TokenLiteral); ok {
-			function, ok := obj.(*object.Function)
+		function := evalCallee(expression.Function, env)
+		if isError(function) {
+			return function
+		}
+		args := []object.Object{}
+		for _, param := range expression.Parameters {
+			res := Eval(param, env)
+			if isError(res) {
+				return res
+			}
+			args = append(args, res)
+		}
+		return withFrame(ApplyFunction(function, args, env.Runtime), expression)
+
+	default:
+		return object.NULL
+	}
+}
+
+func evalForEachLoop(loop *ast.ForEachLoop, env *object.Environment) object.Object {
+	iterable := Eval(loop.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+	var result object.Object
+	result = object.NULL
+	// each runs the body for one item, returning false to stop the loop
+	each := func(item object.Object) bool {
+		env.Scope().Set(loop.Variable.Token.TokenLiteral, item)
+		result = EvalStatements(loop.Body.Statements, env, false)
+		if isError(result) || result.Type() == object.RETURN_OBJ {
+			return false
+		}
+		if result.Type() == object.BREAK_OBJ {
+			result = object.NULL
+			return false
+		}
+		if result.Type() == object.CONTINUE_OBJ {
+			result = object.NULL
+		}
+		return true
+	}
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		for _, item := range iterable.Items {
+			if !each(item) {
+				break
+			}
+		}
+	case *object.String:
+		for _, char := range iterable.Value {
+			if !each(&object.String{Value: string(char)}) {
+				break
+			}
+		}
+	case *object.Hash:
+		keys := append([]object.HashKey{}, iterable.Keys...)
+		for _, k := range keys {
+			pair, ok := iterable.Pairs[k]
 			if !ok {
-				return Errorf("type error", "%s expected function type got %T", expression.Identifier.Token.TokenLiteral, obj)
+				// deleted by the loop body
+				continue
 			}
-			childEnv := object.NewEnvironment(env)
-			for i, p := range function.Parameters {
-				childEnv.Set(p.Token.TokenLiteral, Eval(expression.Parameters[i], childEnv))
+			if !each(pair.Key) {
+				break
 			}
-			return EvalStatements(function.Body.Statements, childEnv, true)
-		} else if builtin, ok := Builtins[expression.Identifier.Token.TokenLiteral]; ok {
-			params := []object.Object{}
-			for _, param := range expression.Parameters {
-				res := Eval(param, env)
-				if res.Type() == object.ERROR_OBJ {
-					return res
-				}
-				params = append(params, res)
+		}
+	case *object.Range:
+		for i := 0; i < iterable.Len(); i++ {
+			if !each(&object.Integer{Value: iterable.Start + i*iterable.Step}) {
+				break
 			}
-			return builtin(params...)
 		}
-		return Errorf("undefined variable", "%s is used before assignment", expression.Identifier.Token.TokenLiteral)
+	default:
+		return Errorf("type error", "cannot iterate over %s", iterable.Type())
+	}
+	return result
+}
+
+// evalMatchExpression runs the first arm whose pattern matches, bindings made
+// by the pattern are only visible inside the arm but other variables it sets
+// are kept
+func evalMatchExpression(match *ast.MatchExpression, env *object.Environment) object.Object {
+	value := Eval(match.Value, env)
+	if isError(value) {
+		return value
+	}
+	for _, arm := range match.Arms {
+		armEnv := object.NewBlockEnvironment(env)
+		if arm.Pattern == nil || matchPattern(arm.Pattern, value, armEnv) {
+			return EvalStatements(arm.Body.Statements, armEnv, false)
+		}
+	}
+	return object.NULL
+}
 
+func matchPattern(pattern ast.Expression, value object.Object, env *object.Environment) bool {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Token.TokenLiteral != "_" {
+			env.Set(pattern.Token.TokenLiteral, value)
+		}
+		return true
+	case *ast.ArrayLiteral:
+		arr, ok := value.(*object.Array)
+		if !ok || len(arr.Items) != len(pattern.Items) {
+			return false
+		}
+		for i, item := range pattern.Items {
+			if !matchPattern(item, arr.Items[i], env) {
+				return false
+			}
+		}
+		return true
+	}
+	return valuesEqual(Eval(pattern, env), value)
+}
+
+// valuesEqual compares numbers by value and other hashable objects by key
+func valuesEqual(left object.Object, right object.Object) bool {
+	if isNumber(left) && isNumber(right) {
+		return toFloat(left).Value == toFloat(right).Value
+	}
+	l, ok := left.(object.Hashable)
+	if !ok {
+		return false
+	}
+	r, ok := right.(object.Hashable)
+	if !ok {
+		return false
+	}
+	return l.HashKey() == r.HashKey()
+}
+
+// evalCallee evaluates the function part of a call, falling back to
+// builtins for names that are not bound.
+func evalCallee(callee ast.Expression, env *object.Environment) object.Object {
+	if id, ok := callee.(*ast.Identifier); ok {
+		name := id.Token.TokenLiteral
+		if val, ok := env.Get(name); ok {
+			return val
+		}
+		if builtin, ok := runtimeBuiltins(env.Runtime).Get(name); ok {
+			return builtin
+		}
+		return Errorf("undefined variable", "%s is used before assignment", name)
+	}
+	return Eval(callee, env)
+}
+
+// ApplyFunction calls a user function or builtin with evaluated arguments,
+// builtins run in rt.
+func ApplyFunction(fn object.Object, args []object.Object, rt *object.Runtime) object.Object {
+	switch fn := fn.(type) {
+	case *object.Function:
+		if len(args) != len(fn.Parameters) {
+			return Errorf("wrong number of arguments", "expected %d args got %d", len(fn.Parameters), len(args))
+		}
+		childEnv := object.NewEnvironment(fn.Env)
+		for i, p := range fn.Parameters {
+			childEnv.Set(p.Token.TokenLiteral, args[i])
+		}
+		return EvalStatements(fn.Body.Statements, childEnv, true)
+	case *object.BuiltIn:
+		if !fn.Accepts(len(args)) {
+			return Errorf("wrong number of arguments", "%s expected %s args got %d", fn.Name, fn.Arity(), len(args))
+		}
+		return fn.Fn(rt, args...)
 	default:
+		return Errorf("type error", "expected function type got %s", fn.Type())
+	}
+}
+
+// applyCallback calls fn from a builtin, adding a frame for fn to errors as
+// a direct call would. The frame gets the position of the builtin's call.
+func applyCallback(fn object.Object, args []object.Object, rt *object.Runtime) object.Object {
+	result := ApplyFunction(fn, args, rt)
+	if err, ok := result.(*object.Error); ok {
+		err.Stack = append(err.Stack, object.Frame{Function: functionName(fn)})
+	}
+	return result
+}
+
+// withFrame records the call an error unwound through, building its traceback.
+func withFrame(result object.Object, call *ast.FunctionCallExpression) object.Object {
+	if err, ok := result.(*object.Error); ok {
+		for i := range err.Stack {
+			if !err.Stack[i].Pos.IsValid() {
+				// called by a builtin
+				err.Stack[i].Pos = call.Pos()
+			}
+		}
+		err.Stack = append(err.Stack, object.Frame{Function: calleeName(call.Function), Pos: call.Pos()})
+	}
+	return result
+}
+
+func functionName(fn object.Object) string {
+	switch fn := fn.(type) {
+	case *object.Function:
+		if fn.Name != "" {
+			return fn.Name
+		}
+	case *object.BuiltIn:
+		return fn.Name
+	}
+	return "<匿名函數 anonymous function>"
+}
+
+func calleeName(callee ast.Expression) string {
+	if _, ok := callee.(*ast.FunctionLiteral); ok {
+		return "<匿名函數 anonymous function>"
+	}
+	return callee.String()
+}
+
+func evalMemberExpression(left object.Object, name string) object.Object {
+	switch left := left.(type) {
+	case *object.Module:
+		if val, ok := left.Get(name); ok {
+			return val
+		}
+		return Errorf("undefined variable", "module %s has no member %s", left.Name, name)
+	case *object.Hash, *object.ErrorValue:
+		return evalIndexExpression(left, &object.String{Value: name})
+	default:
+		return Errorf("type error", "%s has no members", left.Type())
+	}
+}
+
+func evalIndexExpression(left object.Object, index object.Object) object.Object {
+	if hash, ok := left.(*object.Hash); ok {
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return Errorf("type error", "unusable as hash key: %s", index.Type())
+		}
+		if val, ok := hash.Get(key); ok {
+			return val
+		}
 		return object.NULL
 	}
+	if errVal, ok := left.(*object.ErrorValue); ok {
+		name, ok := index.(*object.String)
+		if !ok {
+			return Errorf("type error", "error field must be string")
+		}
+		if val, ok := errVal.Field(name.Value); ok {
+			return val
+		}
+		return Errorf("index error", "error has no field %s", name.Value)
+	}
+	idx, ok := index.(*object.Integer)
+	if !ok {
+		return Errorf("type error", "index must be number")
+	}
+	switch left := left.(type) {
+	case *object.Array:
+		if idx.Value < 0 || idx.Value >= len(left.Items) {
+			return Errorf("index error", "list index out of range")
+		}
+		return left.Items[idx.Value]
+	case *object.String:
+		runes := left.Runes()
+		if idx.Value < 0 || idx.Value >= len(runes) {
+			return Errorf("index error", "string index out of range")
+		}
+		return &object.String{Value: string(runes[idx.Value])}
+	default:
+		return Errorf("type error", "cannot index %s", left.Type())
+	}
 }
 
 func isTruthy(obj object.Object) bool {
@@ -201,74 +573,164 @@ func isTruthy(obj object.Object) bool {
 	return true
 }
 
+// evalLogicalExpression only evaluates the right side when the left side
+// does not decide the result
+func evalLogicalExpression(left object.Object, expression *ast.InfixExpression, env *object.Environment) object.Object {
+	if expression.Infix.TokenType == token.AND && !isTruthy(left) {
+		return object.FALSE
+	}
+	if expression.Infix.TokenType == token.OR && isTruthy(left) {
+		return object.TRUE
+	}
+	right := Eval(expression.Right, env)
+	if isError(right) {
+		return right
+	}
+	return getBoolObj(isTruthy(right))
+}
+
 func evalInfixExpression(left object.Object, right object.Object, infix token.Token) object.Object {
-	// + - * / 係 細過 大過
-	if object.ERROR.Message != "" {
-		return object.ERROR
+	// + - * / 係 唔係咁 細過 大過 唔大過 唔細過
+	if isNumber(left) && isNumber(right) && left.Type() != right.Type() {
+		// int and float mix, promote both to float
+		return evalFloatInfixExpression(toFloat(left), toFloat(right), infix)
 	}
 	if left.Type() != right.Type() {
-		return Errorf("type mismatch", "%T (%+v) %s %T (%+v)", left, left, infix.TokenLiteral, right, right)
+		return infixErrorf("type mismatch", left, right, infix)
 	}
-	lInt, l_ok := left.(*object.Integer)
-	rInt, r_ok := right.(*object.Integer)
+	switch left := left.(type) {
+	case *object.Integer:
+		return evalIntegerInfixExpression(left, right.(*object.Integer), infix)
+	case *object.Float:
+		return evalFloatInfixExpression(left, right.(*object.Float), infix)
+	case *object.String:
+		return evalStringInfixExpression(left, right.(*object.String), infix)
+	case *object.Boolean:
+		switch infix.TokenType {
+		case token.EQUAL_TO:
+			return getBoolObj(left == right)
+		case token.NOT_EQUAL_TO:
+			return getBoolObj(left != right)
+		}
+	}
+	return invalidInfix(left, right, infix)
+}
+
+func evalIntegerInfixExpression(left *object.Integer, right *object.Integer, infix token.Token) object.Object {
 	switch infix.TokenType {
 	case token.ADD:
-		if l_ok && r_ok {
-			return &object.Integer{Value: lInt.Value + rInt.Value}
-		}
-		if left.Type() == object.STRING_OBJ {
-			return &object.String{Value: left.(*object.String).Value + right.(*object.String).Value}
-		}
-		return Errorf("invalid operation", "%T (%+v) %s %T (%+v)", left, left, infix.TokenLiteral, right, right)
+		return &object.Integer{Value: left.Value + right.Value}
 	case token.MINUS:
-		if l_ok && r_ok {
-			return &object.Integer{Value: lInt.Value - rInt.Value}
-		}
-		return Errorf("invalid operation", "%T (%+v) %s %T (%+v)", left, left, infix.TokenLiteral, right, right)
+		return &object.Integer{Value: left.Value - right.Value}
 	case token.MULTIPLY:
-		if l_ok && r_ok {
-			return &object.Integer{Value: lInt.Value * rInt.Value}
-		}
-		return Errorf("invalid operation", "%T (%+v) %s %T (%+v)", left, left, infix.TokenLiteral, right, right)
+		return &object.Integer{Value: left.Value * right.Value}
 	case token.DIVIDE:
-		if l_ok && r_ok {
-			return &object.Integer{Value: lInt.Value / rInt.Value}
+		if right.Value == 0 {
+			return infixErrorf("division by zero", left, right, infix)
 		}
-		return Errorf("invalid operation", "%T (%+v) %s %T (%+v)", left, left, infix.TokenLiteral, right, right)
+		return &object.Integer{Value: left.Value / right.Value}
+	case token.EQUAL_TO:
+		return getBoolObj(left.Value == right.Value)
+	case token.NOT_EQUAL_TO:
+		return getBoolObj(left.Value != right.Value)
 	case token.LESS_THAN:
-		if l_ok && r_ok {
-			return getBoolObj(lInt.Value < rInt.Value)
-		}
-		return Errorf("invalid comparison", "%T (%+v) %s %T (%+v)", left, left, infix.TokenLiteral, right, right)
+		return getBoolObj(left.Value < right.Value)
 	case token.GREATER_THAN:
-		if l_ok && r_ok {
-			return getBoolObj(lInt.Value > rInt.Value)
+		return getBoolObj(left.Value > right.Value)
+	case token.LESS_EQUAL:
+		return getBoolObj(left.Value <= right.Value)
+	case token.GREATER_EQUAL:
+		return getBoolObj(left.Value >= right.Value)
+	}
+	return invalidInfix(left, right, infix)
+}
+
+func evalFloatInfixExpression(left *object.Float, right *object.Float, infix token.Token) object.Object {
+	switch infix.TokenType {
+	case token.ADD:
+		return &object.Float{Value: left.Value + right.Value}
+	case token.MINUS:
+		return &object.Float{Value: left.Value - right.Value}
+	case token.MULTIPLY:
+		return &object.Float{Value: left.Value * right.Value}
+	case token.DIVIDE:
+		if right.Value == 0 {
+			return infixErrorf("division by zero", left, right, infix)
 		}
-		return Errorf("invalid comparison", "%T (%+v) %s %T (%+v)", left, left, infix.TokenLiteral, right, right)
+		return &object.Float{Value: left.Value / right.Value}
 	case token.EQUAL_TO:
-		if l_ok && r_ok {
-			return getBoolObj(lInt.Value == rInt.Value)
-		}
-		if left.Type() == object.STRING_OBJ {
-			return getBoolObj(left.(*object.String).Value == right.(*object.String).Value)
-		}
-		if left.Type() == object.BOOL_OBJ {
-			return getBoolObj(left == right)
-		}
-		return Errorf("invalid comparison", "%T (%+v) %s %T (%+v)", left, left, infix.TokenLiteral, right, right)
+		return getBoolObj(left.Value == right.Value)
+	case token.NOT_EQUAL_TO:
+		return getBoolObj(left.Value != right.Value)
+	case token.LESS_THAN:
+		return getBoolObj(left.Value < right.Value)
+	case token.GREATER_THAN:
+		return getBoolObj(left.Value > right.Value)
+	case token.LESS_EQUAL:
+		return getBoolObj(left.Value <= right.Value)
+	case token.GREATER_EQUAL:
+		return getBoolObj(left.Value >= right.Value)
 	}
-	// invalid infix
-	return Errorf("invalid infix", "%T (%+v) %s %T (%+v)", left, left, infix.TokenLiteral, right, right)
+	return invalidInfix(left, right, infix)
+}
+
+func isNumber(obj object.Object) bool {
+	return obj.Type() == object.INT_OBJ || obj.Type() == object.FLOAT_OBJ
+}
+
+func toFloat(obj object.Object) *object.Float {
+	if i, ok := obj.(*object.Integer); ok {
+		return &object.Float{Value: float64(i.Value)}
+	}
+	return obj.(*object.Float)
+}
+
+// evalStringInfixExpression compares strings character by character, utf-8
+// sorts in the same order as the runes it encodes
+func evalStringInfixExpression(left *object.String, right *object.String, infix token.Token) object.Object {
+	switch infix.TokenType {
+	case token.ADD:
+		return &object.String{Value: left.Value + right.Value}
+	case token.EQUAL_TO:
+		return getBoolObj(left.Value == right.Value)
+	case token.NOT_EQUAL_TO:
+		return getBoolObj(left.Value != right.Value)
+	case token.LESS_THAN:
+		return getBoolObj(left.Value < right.Value)
+	case token.GREATER_THAN:
+		return getBoolObj(left.Value > right.Value)
+	case token.LESS_EQUAL:
+		return getBoolObj(left.Value <= right.Value)
+	case token.GREATER_EQUAL:
+		return getBoolObj(left.Value >= right.Value)
+	}
+	return invalidInfix(left, right, infix)
+}
+
+func invalidInfix(left object.Object, right object.Object, infix token.Token) *object.Error {
+	switch infix.TokenType {
+	case token.ADD, token.MINUS, token.MULTIPLY, token.DIVIDE:
+		return infixErrorf("invalid operation", left, right, infix)
+	case token.EQUAL_TO, token.NOT_EQUAL_TO, token.LESS_THAN, token.GREATER_THAN, token.LESS_EQUAL, token.GREATER_EQUAL:
+		return infixErrorf("invalid comparison", left, right, infix)
+	}
+	return infixErrorf("invalid infix", left, right, infix)
+}
+
+func infixErrorf(message string, left object.Object, right object.Object, infix token.Token) *object.Error {
+	return Errorf(message, "%T (%+v) %s %T (%+v)", left, left, infix.TokenLiteral, right, right)
 }
 
 func evalPrefixExpression(tokenType string, right object.Object) object.Object {
 	switch tokenType {
 	case token.MINUS:
-		rightInt, ok := right.(*object.Integer)
-		if !ok {
-			return Errorf("invalid prefix", "%s %T (%+v)", tokenType, right, right)
+		switch right := right.(type) {
+		case *object.Integer:
+			return &object.Integer{Value: -right.Value}
+		case *object.Float:
+			return &object.Float{Value: -right.Value}
 		}
-		return &object.Integer{Value: -rightInt.Value}
+		return Errorf("invalid prefix", "%s %T (%+v)", tokenType, right, right)
 	case token.NOT:
 		rightBool, ok := right.(*object.Boolean)
 		if !ok {
@@ -288,7 +750,23 @@ func getBoolObj(b bool) *object.Boolean {
 }
 
 func Errorf(message, descrigtionFormat string, a ...interface{}) *object.Error {
-	object.ERROR.Message = message
-	object.ERROR.Description = fmt.Sprintf(descrigtionFormat, a...)
-	return object.ERROR
+	return &object.Error{Message: message, Description: fmt.Sprintf(descrigtionFormat, a...)}
+}
+
+func isError(obj object.Object) bool {
+	return obj != nil && obj.Type() == object.ERROR_OBJ
+}
+
+// evalTemplateLiteral joins the parts of a string, values other than strings
+// are written as they are printed
+func evalTemplateLiteral(template *ast.TemplateLiteral, env *object.Environment) object.Object {
+	buff := strings.Builder{}
+	for _, part := range template.Parts {
+		value := Eval(part, env)
+		if isError(value) {
+			return value
+		}
+		buff.WriteString(toString(value))
+	}
+	return &object.String{Value: buff.String()}
 }
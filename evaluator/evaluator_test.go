@@ -1,9 +1,12 @@
 package evaluator
 
 import (
+	"bytes"
 	"cantolang/lexer"
 	"cantolang/object"
 	"cantolang/parser"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -36,6 +39,10 @@ func TestInteger(t *testing.T) {
 		{"[1,2,3][1+1]", 3},
 		{"塞 3 入 i; i 大D; i;", 4},
 		{"塞 3 入 i; i 細D; i;", 2},
+		{"塞 三十五 入 x。 x。", 35},
+		{"廿一 + 一百零五", 126},
+		{"１２ * 十", 120},
+		{"塞 二 入 一齊。 一齊 + 一", 3},
 	}
 	for _, test := range tests {
 		output := testEval(t, test.input)
@@ -49,6 +56,37 @@ func TestInteger(t *testing.T) {
 	}
 }
 
+func TestFloat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0.5", "0.5"},
+		{"0．5", "0.5"},
+		{"-1.25", "-1.25"},
+		{"7.0 / 2", "3.5"},
+		{"7 除 2.0", "3.5"},
+		{"1 + 0.5", "1.5"},
+		{"0.5 * 4", "2.0"},
+		{"2.5 - 3", "-0.5"},
+		{"塞 1.5 入 x; x 大D; x;", "2.5"},
+		{"塞 1.5 入 x; x 細D; x;", "0.5"},
+		{"[1.0, 2]", "[1.0, 2]"},
+	}
+	for _, test := range tests {
+		output := testEval(t, test.input)
+		if output.Inspect() != test.expected {
+			t.Errorf("%s: expected %s got %s (%T)", test.input, test.expected, output.Inspect(), output)
+		}
+	}
+	if output := testEval(t, "1 + 1.0"); output.Type() != object.FLOAT_OBJ {
+		t.Errorf("expected float got %s", output.Type())
+	}
+	if output := testEval(t, "7 / 2"); output.Type() != object.INT_OBJ {
+		t.Errorf("expected int got %s", output.Type())
+	}
+}
+
 func TestBool(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -67,6 +105,42 @@ func TestBool(t *testing.T) {
 		{"唔係 唔係(6 大過 3)", true},
 		{`"hi" 係 "amogus"`, false},
 		{`"fart" 係 "fart"`, true},
+		{"啱 同埋 啱", true},
+		{"啱 同埋 錯", false},
+		{"錯 同埋 啱", false},
+		{"錯 或者 啱", true},
+		{"錯 或者 錯", false},
+		{"1 細過 2 同埋 2 細過 3", true},
+		{"1 大過 2 或者 2 細過 3 同埋 錯", false},
+		{"唔係 錯 同埋 啱", true},
+		// the right side is not evaluated when the left decides
+		{"錯 同埋 (1 + 啱)", false},
+		{"啱 或者 (1 + 啱)", true},
+		{"錯 同埋 undefined（）", false},
+		{"3 唔係咁 3", false},
+		{"3 唔係咁 4", true},
+		{"3 唔大過 3", true},
+		{"4 唔大過 3", false},
+		{"3 唔細過 3", true},
+		{"2 唔細過 3", false},
+		{"3 == 3", true},
+		{"3 != 3", false},
+		{"2 < 3", true},
+		{"2 > 3", false},
+		{"3 <= 2", false},
+		{"3 >= 2", true},
+		{`"a" < "b"`, true},
+		{`"b" <= "a"`, false},
+		{`"甲" >= "甲"`, true},
+		{`"hi" != "hi"`, false},
+		{`"hi" 唔係咁 "ho"`, true},
+		{"啱 != 錯", true},
+		{"啱 == 啱", true},
+		{"1 係 1.0", true},
+		{"1.5 大過 1", true},
+		{"2 細過 1.5", false},
+		{"0.1 + 0.2 唔細過 0.3", true},
+		{"1.5 != 1.5", false},
 	}
 	for _, test := range tests {
 		output := testEval(t, test.input)
@@ -95,6 +169,48 @@ func TestError(t *testing.T) {
 		{"如果 (啱 大過 錯) 嘅話，就 {2} 唔係就 {3}", "invalid comparison"},
 		{`有幾長（2）`, "invalid argument type"},
 		{`"hi"[2]`, "index error"},
+		{`聽到 one（x） 嘅話，就「 俾我 x。 」; one（1，2）`, "wrong number of arguments"},
+		{`塞 1 入 x; x（）`, "type error"},
+		{`missing（）`, "undefined variable"},
+		{"啱 同埋 (1 + 啱)", "type mismatch"},
+		{"錯 或者 (1 + 啱)", "type mismatch"},
+		{"啱 <= 錯", "invalid comparison"},
+		{`"a" - "b"`, "invalid operation"},
+		{"[1] == [1]", "invalid comparison"},
+		{"1 / 0", "division by zero"},
+		{"1.5 / 0", "division by zero"},
+		{"1.5 + 啱", "type mismatch"},
+		{`中文數字（"十"）`, "invalid argument type"},
+		{`{[1]: 2}`, "type error"},
+		{`{1: 2}[[1]]`, "type error"},
+		{`所有鍵（[1]）`, "invalid argument type"},
+		{`有冇（{}, 1.5）`, "type error"},
+		{`塞 [1] 入 a; 塞 2 入 a[1]`, "index error"},
+		{`塞 [1] 入 a; 塞 2 入 a[-1]`, "index error"},
+		{`塞 [1] 入 a; 塞 2 入 a["x"]`, "type error"},
+		{`塞 "hi" 入 s; 塞 "a" 入 s[0]`, "type error"},
+		{`{"a": 1}.b.c`, "type error"},
+		{`試下「 1 / 0 」出事就（e）「 e.code 」`, "index error"},
+		{`塞 1 入 a; 塞 2 入 a[0]`, "type error"},
+		{`逐個 x 喺 1 度，就「 」`, "type error"},
+		{"當 （1 + 啱） 時，就「」", "type mismatch"},
+		{"當 （1 + 啱） 時，就「 停。 」", "type mismatch"},
+		{`塞 0 入 n; 當 （n 細過 2） 時，就「 塞 n+1 入 n。 」; 當 （n + "a"） 時，就「 停。 」`, "type mismatch"},
+		{`塞 0 入 n; 當 （n 細過 2 同埋 n + 啱） 時，就「 塞 n+1 入 n。 」`, "type mismatch"},
+		{`範圍（0，3，0）`, "invalid argument"},
+		{`範圍（"3"）`, "invalid argument type"},
+		{`範圍（）`, "wrong number of arguments"},
+		{`範圍（-9223372036854775807，9223372036854775807）`, "invalid argument"},
+		{`範圍（9223372036854775807，-9223372036854775807，-1）`, "invalid argument"},
+		{`轉數字（"abc"）`, "invalid argument"},
+		{`轉數字（""）`, "invalid argument"},
+		{`轉數字（[1]）`, "invalid argument type"},
+		{`聽晒（1）`, "wrong number of arguments"},
+		{`掟（"bad input"）`, "bad input"},
+		{`掟（1）`, "invalid argument type"},
+		{`試下「 1 / 0 」出事就「 [1][5] 」`, "index error"},
+		{`試下「 1 / 0 」出事就（e）「 e["code"] 」`, "index error"},
+		{`試下「 1 / 0 」出事就（e）「 掟（e） 」`, "division by zero"},
 	}
 	for _, test := range tests {
 		output := testEval(t, test.input)
@@ -108,6 +224,150 @@ func TestError(t *testing.T) {
 	}
 }
 
+func TestErrorPosition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 + 啱", "test.txt:1:1"},
+		{"塞 1 入 i。\n塞 i + (2 * 錯) 入 j。", "test.txt:2:8"},
+		{"\n\n  [1,2][5]", "test.txt:3:3"},
+	}
+	for _, test := range tests {
+		l := lexer.NewFile("test.txt", test.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		output := Eval(program, object.NewEnvironment(nil))
+		err, ok := output.(*object.Error)
+		if !ok {
+			t.Errorf("Expected object.Error got %T", output)
+			continue
+		}
+		if err.Pos.String() != test.expected {
+			t.Errorf("Expected position %s got %s", test.expected, err.Pos)
+		}
+	}
+}
+
+func TestTraceback(t *testing.T) {
+	input := `聽到 inner（x） 嘅話，就「
+	俾我 x + 啱。
+」
+聽到 outer（x） 嘅話，就「
+	俾我 inner（x）。
+」
+outer（1 + 1）。`
+	l := lexer.NewFile("test.txt", input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	output := Eval(program, object.NewEnvironment(nil))
+	err, ok := output.(*object.Error)
+	if !ok {
+		t.Fatalf("Expected object.Error got %T", output)
+	}
+	expected := []struct {
+		function string
+		pos      string
+	}{
+		{"inner", "test.txt:5:5"},
+		{"outer", "test.txt:7:1"},
+	}
+	if len(err.Stack) != len(expected) {
+		t.Fatalf("Expected %d frames got %d", len(expected), len(err.Stack))
+	}
+	for i, frame := range expected {
+		if err.Stack[i].Function != frame.function || err.Stack[i].Pos.String() != frame.pos {
+			t.Errorf("[%d] Expected frame %s at %s got %+v", i, frame.function, frame.pos, err.Stack[i])
+		}
+	}
+	traceback := `追蹤（最近嘅呼叫喺最尾）traceback (most recent call last):
+  test.txt:7:1 叫 call outer
+  test.txt:5:5 叫 call inner
+test.txt:2:5: *object.Integer (&{Value:2}) + *object.Boolean (&{Value:true}): type mismatch`
+	if err.Traceback() != traceback {
+		t.Errorf("Expected traceback:\n%s\ngot:\n%s", traceback, err.Traceback())
+	}
+
+	// errors in arguments are raised by the caller
+	output = testEval(t, `有幾長（1 + 啱）`)
+	err, ok = output.(*object.Error)
+	if !ok {
+		t.Fatalf("Expected object.Error got %T", output)
+	}
+	if len(err.Stack) != 0 {
+		t.Errorf("Expected no frames got %+v", err.Stack)
+	}
+
+	// callbacks called by builtins get a frame like a direct call
+	callbacks := []struct {
+		input     string
+		traceback string
+	}{
+		{`聽到 bad（x） 嘅話，就「
+	俾我 x + 啱。
+」
+變晒（【1】，bad）。`, `追蹤（最近嘅呼叫喺最尾）traceback (most recent call last):
+  test.txt:4:1 叫 call 變晒
+  test.txt:4:1 叫 call bad
+test.txt:2:5: *object.Integer (&{Value:1}) + *object.Boolean (&{Value:true}): type mismatch`},
+		{`排序（【1，2】，聽到（a，b） 嘅話，就「 俾我 a + 啱。 」）。`, `追蹤（最近嘅呼叫喺最尾）traceback (most recent call last):
+  test.txt:1:1 叫 call 排序
+  test.txt:1:1 叫 call <匿名函數 anonymous function>
+test.txt:1:27: *object.Integer (&{Value:2}) + *object.Boolean (&{Value:true}): type mismatch`},
+	}
+	for _, test := range callbacks {
+		program := parser.New(lexer.NewFile("test.txt", test.input)).ParseProgram()
+		output = Eval(program, object.NewEnvironment(nil))
+		err, ok = output.(*object.Error)
+		if !ok {
+			t.Fatalf("Expected object.Error got %T", output)
+		}
+		if err.Traceback() != test.traceback {
+			t.Errorf("Expected traceback:\n%s\ngot:\n%s", test.traceback, err.Traceback())
+		}
+	}
+}
+
+func TestErrorIsolation(t *testing.T) {
+	inputs := []string{
+		`
+		聽到 bad（） 嘅話，就「
+		     俾我 1 + 啱。
+		」;
+		bad();
+		`,
+		`
+		塞 0 入 i。
+		當 （i 細過 50） 時，就「
+		    i 大D。
+		」
+		i;`,
+	}
+	var wg sync.WaitGroup
+	for n := 0; n < 20; n++ {
+		for i, input := range inputs {
+			wg.Add(1)
+			go func(i int, input string) {
+				defer wg.Done()
+				output := testEval(t, input)
+				switch i {
+				case 0:
+					err, ok := output.(*object.Error)
+					if !ok || err.Message != "type mismatch" {
+						t.Errorf("expected type mismatch error got %T (%+v)", output, output)
+					}
+				case 1:
+					intObj, ok := output.(*object.Integer)
+					if !ok || intObj.Value != 50 {
+						t.Errorf("expected 50 got %T (%+v)", output, output)
+					}
+				}
+			}(i, input)
+		}
+	}
+	wg.Wait()
+}
+
 func testEval(t *testing.T, input string) object.Object {
 	l := lexer.New(input)
 	p := parser.New(l)
@@ -202,6 +462,159 @@ func TestFunction(t *testing.T) {
 	}
 }
 
+func TestClosure(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{`
+		聽到 makeAdder（x） 嘅話，就「
+			聽到 adder（y） 嘅話，就「
+				俾我 x + y。
+			」
+			俾我 adder。
+		」
+		塞 makeAdder（2） 入 addTwo。
+		塞 makeAdder（10） 入 addTen。
+		addTwo（3） + addTen（3）。
+		`, 18},
+		{`
+		聽到 makeCounter（） 嘅話，就「
+			塞 0 入 count。
+			聽到 counter（） 嘅話，就「
+				count 大D。
+				俾我 count。
+			」
+			俾我 counter。
+		」
+		塞 makeCounter（） 入 a。
+		塞 makeCounter（） 入 b。
+		a（）。
+		a（）。
+		b（）。
+		a（）。
+		`, 3},
+		{`
+		塞 1 入 x。
+		聽到 getX（） 嘅話，就「
+			俾我 x。
+		」
+		聽到 shadow（x） 嘅話，就「
+			俾我 getX（）。
+		」
+		shadow（5）。
+		`, 1},
+		{`
+		聽到 outer（） 嘅話，就「
+			塞 1 入 total。
+			聽到 add（n） 嘅話，就「
+				塞 total + n 入 total。
+			」
+			add（2）。
+			add（3）。
+			俾我 total。
+		」
+		outer（）。
+		`, 6},
+		{`
+		塞 5 入 n。
+		聽到 local（） 嘅話，就「
+			聽到 inner（n） 嘅話，就「
+				塞 n + 1 入 n。
+				俾我 n。
+			」
+			俾我 inner（1）。
+		」
+		local（） + n。
+		`, 7},
+		{`
+		聽到 sumTo（n） 嘅話，就「
+			塞 0 入 i。
+			塞 0 入 total。
+			當 （i 細過 n） 時，就「
+				i 大D。
+				塞 total + i 入 total。
+			」
+			俾我 total。
+		」
+		塞 0 入 i。
+		塞 0 入 calls。
+		當 （i 細過 3） 時，就「
+			sumTo（5）。
+			calls 大D。
+			i 大D。
+		」
+		calls。
+		`, 3},
+		{`
+		塞 1 入 x。
+		聽到 f（） 嘅話，就「
+			塞 2 入 x。
+			俾我 x。
+		」
+		f（） * 10 + x。
+		`, 21},
+		{`
+		塞 1 入 x。
+		如果 （啱） 嘅話，就「 塞 2 入 x。 」
+		試下「 1 / 0 」出事就「 塞 x + 1 入 x。 」
+		x。
+		`, 3},
+	}
+	for i, test := range tests {
+		output := testEval(t, test.input)
+		intObj, ok := output.(*object.Integer)
+		if !ok {
+			t.Errorf("[%d] Expected object.Integer got %T (%+v)", i, output, output)
+			continue
+		}
+		if intObj.Value != test.expected {
+			t.Errorf("[%d] Expected %d got %d", i, test.expected, intObj.Value)
+		}
+	}
+}
+
+func TestFunctionLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{`
+		塞 聽到（x） 嘅話，就「 俾我 x * 2。 」 入 double。
+		double（4）。
+		`, 8},
+		{`
+		聽到 apply（f，x） 嘅話，就「
+			俾我 f（x）。
+		」
+		apply（聽到（n） 嘅話，就「 n + 1 」， 2）。
+		`, 3},
+		{`
+		塞 【聽到（x） 嘅話，就「 x + 1 」， 聽到（x） 嘅話，就「 x * 10 」】 入 fs。
+		fs【0】（1） + fs【1】（1）。
+		`, 12},
+		{`聽到（x，y） 嘅話，就「 俾我 x - y。 」（5，2）`, 3},
+		{`（聽到（） 嘅話，就「 7 」）（）`, 7},
+		{`
+		聽到 adder（x） 嘅話，就「
+			俾我 聽到（y） 嘅話，就「 x + y 」。
+		」
+		adder（1）（2）。
+		`, 3},
+	}
+	for i, test := range tests {
+		output := testEval(t, test.input)
+		intObj, ok := output.(*object.Integer)
+		if !ok {
+			t.Errorf("[%d] Expected object.Integer got %T (%+v)", i, output, output)
+			continue
+		}
+		if intObj.Value != test.expected {
+			t.Errorf("[%d] Expected %d got %d", i, test.expected, intObj.Value)
+		}
+	}
+}
+
 func TestString(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -216,6 +629,9 @@ func TestString(t *testing.T) {
 			i[1];
 		`, "world"},
 		{`"hello"[1]`, "e"},
+		{`中文數字（2024）`, "二千零二十四"},
+		{`中文數字（三十五）`, "三十五"},
+		{`中文數字（10010）`, "一萬零一十"},
 	}
 	for _, test := range tests {
 		output := testEval(t, test.input)
@@ -241,6 +657,9 @@ func TestBuiltInFunction(t *testing.T) {
 		{`加上([1],2,3)[0]`, 1},
 		{`加上([1],2,3)[1]`, 2},
 		{`加上([1],2,3)[2]`, 3},
+		{`塞 有幾長 入 len; len（"abc"）`, 3},
+		{`聽到 apply（f，x） 嘅話，就「 俾我 f（x）。 」; apply（有幾長，[1, 2]）`, 2},
+		{`聽到 有幾長（x） 嘅話，就「 俾我 42。 」; 有幾長（[]）`, 42},
 	}
 	for _, test := range tests {
 		output := testEval(t, test.input)
@@ -277,6 +696,61 @@ func TestArray(t *testing.T) {
 	}
 }
 
+func TestHash(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`「“名”： “阿明”，“歲”： 30」`, `{名: 阿明, 歲: 30}`},
+		{`{}`, `{}`},
+		{`{1: "一", 啱: "true", "二": 2}`, `{1: 一, true: true, 二: 2}`},
+		{`{1: "a", 1: "b"}`, `{1: b}`},
+		{`「“名”： “阿明”」【“名”】`, `阿明`},
+		{`{1 + 1: "二"}[2]`, `二`},
+		{`{啱: 1}[錯]`, `NULL`},
+		{`塞 {"a": [1, 2]} 入 h; h["a"][1]`, `2`},
+		{`所有鍵（{"a": 1, "b": 2}）`, `[a, b]`},
+		{`所有值（{"a": 1, "b": 2}）`, `[1, 2]`},
+		{`有冇（{"a": 1}, "a"）`, `true`},
+		{`有冇（{"a": 1}, "b"）`, `false`},
+		{`刪除（{"a": 1, "b": 2, "c": 3}, "b"）`, `{a: 1, c: 3}`},
+		{`塞 {"a": 1} 入 h; 刪除（h, "a"）; h`, `{a: 1}`},
+		{`有幾長（{"a": 1, "b": 2}）`, `2`},
+	}
+	for _, test := range tests {
+		output := testEval(t, test.input)
+		if output.Inspect() != test.expected {
+			t.Errorf("%s: expected %s got %s (%T)", test.input, test.expected, output.Inspect(), output)
+		}
+	}
+}
+
+func TestIndexAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`塞 [1, 2, 3] 入 arr; 塞 5 入 arr【2】; arr`, `[1, 2, 5]`},
+		{`塞 [[1, 2], [3, 4]] 入 m; 塞 0 入 m[1][0]; m`, `[[1, 2], [0, 4]]`},
+		{`塞 [1, 2] 入 a; 塞 a 入 b; 塞 9 入 b[0]; a`, `[9, 2]`},
+		{`塞 {"a": 1} 入 h; 塞 2 入 h["b"]; 塞 3 入 h["a"]; h`, `{a: 3, b: 2}`},
+		{`塞 {"xs": [1]} 入 h; 塞 2 入 h["xs"][0]; h`, `{xs: [2]}`},
+		{`塞 [0, 0, 0] 入 arr; 塞 0 入 i;
+		當 （i 細過 3） 時，就「
+			塞 i * i 入 arr[i]。
+			i 大D。
+		」
+		arr`, `[0, 1, 4]`},
+		{`塞 [1] 入 arr; 塞 2 入 arr[0]`, `2`},
+	}
+	for _, test := range tests {
+		output := testEval(t, test.input)
+		if output.Inspect() != test.expected {
+			t.Errorf("%s: expected %s got %s (%T)", test.input, test.expected, output.Inspect(), output)
+		}
+	}
+}
+
 func TestWhileLoop(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -294,6 +768,47 @@ func TestWhileLoop(t *testing.T) {
 		    塞 i+1 入 i。
 		」
 		i;`, 0},
+		{`
+		塞 0 入 i。
+		當 （啱） 時，就「
+		    如果 （i 係 5） 嘅話，就「 停。 」
+		    i 大D。
+		」
+		i;`, 5},
+		{`
+		塞 0 入 i。
+		塞 0 入 sum。
+		當 （i 細過 10） 時，就「
+		    i 大D。
+		    如果 （i 大過 3） 嘅話，就「
+		        如果 （i 細過 8） 嘅話，就「 跳過。 」
+		    」
+		    塞 sum + i 入 sum。
+		」
+		sum;`, 1 + 2 + 3 + 8 + 9 + 10},
+		{`
+		塞 0 入 count。
+		塞 0 入 i。
+		當 （i 細過 3） 時，就「
+		    i 大D。
+		    塞 0 入 j。
+		    當 （啱） 時，就「
+		        j 大D。
+		        如果 （j 大過 2） 嘅話，就「 停。 」
+		        count 大D。
+		    」
+		」
+		count;`, 6},
+		{`
+		聽到 find（arr，x） 嘅話，就「
+		    塞 0 入 i。
+		    當 （i 細過 有幾長（arr）） 時，就「
+		        如果 （arr【i】 係 x） 嘅話，就「 停。 」
+		        i 大D。
+		    」
+		    俾我 i。
+		」
+		find（【4，5，6】，5）;`, 1},
 	}
 	for _, test := range tests {
 		output := testEval(t, test.input)
@@ -306,3 +821,601 @@ func TestWhileLoop(t *testing.T) {
 		}
 	}
 }
+
+func TestForEachLoop(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{`
+		塞 0 入 sum。
+		逐個 x 喺 【1，2，3】 度，就「 塞 sum + x 入 sum。 」
+		sum;`, 6},
+		{`
+		塞 0 入 sum。
+		逐個 x 喺 範圍（5） 度，就「 塞 sum + x 入 sum。 」
+		sum;`, 0 + 1 + 2 + 3 + 4},
+		{`
+		塞 0 入 sum。
+		逐個 x 喺 範圍（2，8，3） 度，就「 塞 sum + x 入 sum。 」
+		sum;`, 2 + 5},
+		{`
+		塞 0 入 sum。
+		逐個 x 喺 範圍（5，0，-2） 度，就「 塞 sum + x 入 sum。 」
+		sum;`, 5 + 3 + 1},
+		{`
+		塞 0 入 count。
+		逐個 c 喺 "你好" 度，就「 count 大D。 」
+		count;`, 2},
+		{`
+		塞 {"a": 1, "b": 2, "c": 3} 入 h。
+		塞 0 入 sum。
+		逐個 k 喺 h 度，就「
+		    塞 sum + h【k】 入 sum。
+		    塞 10 入 h【"d"】。
+		」
+		sum;`, 6},
+		{`
+		塞 0 入 sum。
+		逐個 x 喺 【1，2，3，4，5】 度，就「
+		    如果 （x 係 2） 嘅話，就「 跳過。 」
+		    如果 （x 係 4） 嘅話，就「 停。 」
+		    塞 sum + x 入 sum。
+		」
+		sum;`, 1 + 3},
+		{`
+		聽到 find（arr，y） 嘅話，就「
+		    逐個 x 喺 範圍（有幾長（arr）） 度，就「
+		        如果 （arr【x】 係 y） 嘅話，就「 俾我 x。 」
+		    」
+		    俾我 -1。
+		」
+		find（【4，5，6】，6）;`, 2},
+		{`有幾長（範圍（1，10，2））`, 5},
+		{`有幾長（範圍（3，1））`, 0},
+		{`有幾長（範圍（0，9223372036854775807））`, 9223372036854775807},
+		{`有幾長（範圍（-9223372036854775807，9223372036854775807，2））`, 9223372036854775807},
+		{`有幾長（範圍（9223372036854775807，-9223372036854775807，-4））`, 4611686018427387904},
+		{`有幾長（範圍（0，9223372036854775807，4611686018427387904））`, 2},
+		{`
+		塞 0 入 n。
+		逐個 i 喺 範圍（0，9223372036854775807，4611686018427387904） 度，就「 n 大D。 」
+		n`, 2},
+		{`
+		塞 0 入 last。
+		逐個 i 喺 範圍（-9223372036854775807，9223372036854775807，9223372036854775806） 度，就「 塞 i 入 last。 」
+		last`, 9223372036854775805},
+	}
+	for _, test := range tests {
+		output := testEval(t, test.input)
+		intObj, ok := output.(*object.Integer)
+		if !ok {
+			t.Fatalf("Expected object.Integer got %T (%+v)", output, output)
+		}
+		if intObj.Value != test.expected {
+			t.Errorf("expected %d got %+v", test.expected, intObj.Value)
+		}
+	}
+}
+
+func TestElseIf(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"如果 （1 係 1） 嘅話，就「 1 」唔係就如果 （啱） 嘅話，就「 2 」唔係就「 3 」", 1},
+		{"如果 （1 係 2） 嘅話，就「 1 」唔係就如果 （啱） 嘅話，就「 2 」唔係就「 3 」", 2},
+		{"如果 （1 係 2） 嘅話，就「 1 」唔係就如果 （錯） 嘅話，就「 2 」唔係就「 3 」", 3},
+		{"如果 （錯） 嘅話，就「 1 」唔係就 如果 （錯） 嘅話，就「 2 」唔係就 如果 （啱） 嘅話，就「 4 」", 4},
+	}
+	for _, test := range tests {
+		output := testEval(t, test.input)
+		intObj, ok := output.(*object.Integer)
+		if !ok {
+			t.Fatalf("Expected object.Integer got %T (%+v)", output, output)
+		}
+		if intObj.Value != test.expected {
+			t.Errorf("expected %d got %d", test.expected, intObj.Value)
+		}
+	}
+
+	output := testEval(t, "如果 （錯） 嘅話，就「 1 」唔係就如果 （錯） 嘅話，就「 2 」")
+	if output != object.NULL {
+		t.Errorf("expected NULL got %s", output.Inspect())
+	}
+	output = testEval(t, "如果 （錯） 嘅話，就「 1 」唔係就如果 （1 + 啱） 嘅話，就「 2 」")
+	if err, ok := output.(*object.Error); !ok || err.Message != "type mismatch" {
+		t.Errorf("expected type mismatch got %s", output.Inspect())
+	}
+}
+
+func TestMatch(t *testing.T) {
+	classify := `
+	聽到 classify（x） 嘅話，就「
+	    俾我 睇下（x）「
+	        0 就「 “zero” 」
+	        -1 就「 “minus one” 」
+	        “hi” 就「 “greeting” 」
+	        啱 就「 “true” 」
+	        【】 就「 “empty” 」
+	        【1，_】 就「 “starts with one” 」
+	        【a，b】 就「 a + b 」
+	        【【a】，_，c】 就「 a + c 」
+	        其他 就「 “other” 」
+	    」。
+	」
+	`
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"classify（0）", "zero"},
+		{"classify（0.0）", "zero"},
+		{"classify（-1）", "minus one"},
+		{"classify（“hi”）", "greeting"},
+		{"classify（啱）", "true"},
+		{"classify（【】）", "empty"},
+		{"classify（【1，2】）", "starts with one"},
+		{"classify（【“a”，“b”】）", "ab"},
+		{"classify（【【“x”】，1，“y”】）", "xy"},
+		{"classify（【1，2，3】）", "other"},
+		{"classify（錯）", "other"},
+		{"classify（“0”）", "other"},
+	}
+	for _, test := range tests {
+		output := testEval(t, classify+test.input)
+		str, ok := output.(*object.String)
+		if !ok {
+			t.Fatalf("%s: expected object.String got %T (%+v)", test.input, output, output)
+		}
+		if str.Value != test.expected {
+			t.Errorf("%s: expected %s got %s", test.input, test.expected, str.Value)
+		}
+	}
+
+	output := testEval(t, "睇下（3）「 1 就「 1 」 」")
+	if output != object.NULL {
+		t.Errorf("expected NULL when no arm matches got %s", output.Inspect())
+	}
+	output = testEval(t, "睇下（【1】）「 【a】 就「 a 」 」; a")
+	if output != object.NULL {
+		t.Errorf("expected binding to stay inside the arm got %s", output.Inspect())
+	}
+	output = testEval(t, "塞 1 入 x。 睇下（x）「 1 就「 塞 “one” 入 name。 」 」; name")
+	if output.Inspect() != "one" {
+		t.Errorf("expected variable set in the arm to be kept got %s", output.Inspect())
+	}
+	output = testEval(t, "睇下（【2】）「 【n】 就「 塞 n * 10 入 n。 塞 n 入 m。 」 」; 【m，n】")
+	if output.Inspect() != "[20, NULL]" {
+		t.Errorf("expected pattern binding to stay inside the arm got %s", output.Inspect())
+	}
+}
+
+func TestTry(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`試下「 "ok" 」出事就「 "caught" 」`, "ok"},
+		{`試下「 【1】【5】。 "ok" 」出事就「 "caught" 」`, "caught"},
+		{`試下「 1 + 啱 」出事就（e）「 e【"訊息"】 」`, "type mismatch"},
+		{`試下「 1 + 啱 」出事就（e）「 e【"message"】 」`, "type mismatch"},
+		{`試下「 1 + 啱 」出事就（e）「 e 嘅 訊息 」`, "type mismatch"},
+		{`塞 {"名": "阿明"} 入 h; h.名`, "阿明"},
+		{`試下「 掟（"too big"，"x > 10"） 」出事就（e）「 e【"描述"】 」`, "x > 10"},
+		{`試下「 掟（"too big"） 」出事就（e）「 e【"description"】 」`, ""},
+		{`
+		聽到 check（x） 嘅話，就「
+		    如果 （x 大過 10） 嘅話，就「 掟（"too big"）。 」
+		    俾我 "fine"。
+		」
+		試下「 check（20） 」出事就（e）「 e【"訊息"】 」`, "too big"},
+		{`
+		聽到 safe（x） 嘅話，就「
+		    俾我 試下「 100 / x 」出事就「 "none" 」。
+		」
+		safe（0）`, "none"},
+		{`
+		試下「
+		    試下「 1 / 0 」出事就（e）「 掟（e） 」
+		」出事就（outer）「 outer【"訊息"】 」`, "division by zero"},
+		{`
+		塞 "" 入 log。
+		逐個 x 喺 【1，0，2】 度，就「
+		    試下「
+		        塞 log + "ok" 入 log。
+		        如果 （x 係 2） 嘅話，就「 停。 」
+		        塞 10 / x 入 y。
+		    」出事就「
+		        塞 log + "err" 入 log。
+		        跳過。
+		    」
+		」
+		log`, "okokerrok"},
+		{`試下「 1 / 0 」出事就（e）「 塞 “caught” 入 status。 」; status`, "caught"},
+		{`塞 “e” 入 e。 試下「 1 / 0 」出事就（e）「 塞 e.訊息 入 msg。 」; e + msg`, "edivision by zero"},
+	}
+	for _, test := range tests {
+		output := testEval(t, test.input)
+		str, ok := output.(*object.String)
+		if !ok {
+			t.Fatalf("%s: expected object.String got %T (%+v)", test.input, output, output)
+		}
+		if str.Value != test.expected {
+			t.Errorf("%s: expected %q got %q", test.input, test.expected, str.Value)
+		}
+	}
+
+	output := testEval(t, `試下「 1 / 0 」出事就（e）「 e 」`)
+	errVal, ok := output.(*object.ErrorValue)
+	if !ok {
+		t.Fatalf("expected object.ErrorValue got %T", output)
+	}
+	if errVal.Error.Pos.Line != 1 || errVal.Error.Pos.Column != 5 {
+		t.Errorf("expected error at 1:5 got %s", errVal.Error.Pos)
+	}
+}
+
+func TestBuiltInValue(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`有幾長`, "<內置函數 builtin 有幾長>"},
+		{`[講, 加上]`, "[<內置函數 builtin 講>, <內置函數 builtin 加上>]"},
+		{`說明（有幾長）`, "有幾長（x）length of a string, array, hash or range"},
+		{`missing`, "NULL"},
+	}
+	for _, test := range tests {
+		output := testEval(t, test.input)
+		if output.Inspect() != test.expected {
+			t.Errorf("%s: expected %s got %s", test.input, test.expected, output.Inspect())
+		}
+	}
+
+	arity := []struct {
+		input       string
+		description string
+	}{
+		{`有幾長（）`, "有幾長 expected 1 args got 0"},
+		{`講（）`, "講 expected 1 or more args got 0"},
+		{`範圍（1，2，3，4）`, "範圍 expected 1 to 3 args got 4"},
+		{`塞 加上 入 f; f（[1]）`, "加上 expected 2 or more args got 1"},
+	}
+	for _, test := range arity {
+		output := testEval(t, test.input)
+		err, ok := output.(*object.Error)
+		if !ok {
+			t.Fatalf("%s: expected object.Error got %s", test.input, output.Inspect())
+		}
+		if err.Message != "wrong number of arguments" || err.Description != test.description {
+			t.Errorf("%s: expected %s got %s: %s", test.input, test.description, err.Message, err.Description)
+		}
+	}
+}
+
+func TestRuntimeBuiltins(t *testing.T) {
+	env := object.NewEnvironment(nil)
+	env.Runtime.Builtins = Builtins.Copy()
+	env.Runtime.Builtins.Register(&object.BuiltIn{
+		Name:    "雙",
+		MinArgs: 1,
+		MaxArgs: 1,
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return &object.Integer{Value: args[0].(*object.Integer).Value * 2}
+		},
+	})
+	env.Runtime.Builtins.Remove("講")
+
+	p := parser.New(lexer.New(`雙（有幾長（[1, 2, 3]））`))
+	if output := Eval(p.ParseProgram(), env); output.Inspect() != "6" {
+		t.Errorf("expected 6 got %s", output.Inspect())
+	}
+	p = parser.New(lexer.New(`講（1）`))
+	if err, ok := Eval(p.ParseProgram(), env).(*object.Error); !ok || err.Message != "undefined variable" {
+		t.Errorf("expected 講 to be removed")
+	}
+	// the defaults are unchanged
+	if _, ok := Builtins.Get("雙"); ok {
+		t.Errorf("expected 雙 only in the runtime registry")
+	}
+	if _, ok := Builtins.Get("講"); !ok {
+		t.Errorf("expected 講 in the default registry")
+	}
+}
+
+func TestPrint(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`講（1）`, "1\n"},
+		{`講（"你好"，2.5，[1, "a"]，啱）`, "你好 2.5 [1, a] true\n"},
+		{`逐個 i 喺 範圍（3） 度，就「 講（i）。 」`, "0\n1\n2\n"},
+		{`聽到 f（） 嘅話，就「 講（"in f"）。 」; f（）`, "in f\n"},
+		{`講（1 / 0）`, ""},
+	}
+	for _, test := range tests {
+		out := &bytes.Buffer{}
+		env := object.NewEnvironment(nil)
+		env.Runtime.Stdout = out
+		p := parser.New(lexer.New(test.input))
+		Eval(p.ParseProgram(), env)
+		if out.String() != test.expected {
+			t.Errorf("%s: expected %q got %q", test.input, test.expected, out.String())
+		}
+	}
+}
+
+func TestInput(t *testing.T) {
+	tests := []struct {
+		input    string
+		stdin    string
+		expected string
+		stdout   string
+	}{
+		{`聽（）`, "你好\n世界\n", "你好", ""},
+		{`聽（）; 聽（）`, "你好\r\n世界", "世界", ""},
+		{`聽（）; 聽（）`, "你好\n", "NULL", ""},
+		{`聽（）`, "", "NULL", ""},
+		{`聽（"名？"）`, "阿明\n", "阿明", "名？"},
+		{`聽（）; 聽晒（）`, "1\n2\n3\n", "2\n3\n", ""},
+		{`聽晒（）; 聽晒（）`, "1\n", "NULL", ""},
+		{`轉數字（聽（）） + 轉數字（聽（））`, "12\n 30 \n", "42", ""},
+		{`
+		塞 0 入 sum。
+		塞 聽（） 入 line。
+		當 （line） 時，就「
+		    塞 sum + 轉數字（line） 入 sum。
+		    塞 聽（） 入 line。
+		」
+		sum`, "1\n二\n3", "6", ""},
+	}
+	for _, test := range tests {
+		out := &bytes.Buffer{}
+		env := object.NewEnvironment(nil)
+		env.Runtime.Stdout = out
+		env.Runtime.Stdin = strings.NewReader(test.stdin)
+		p := parser.New(lexer.New(test.input))
+		output := Eval(p.ParseProgram(), env)
+		if output.Inspect() != test.expected {
+			t.Errorf("%s: expected %q got %q", test.input, test.expected, output.Inspect())
+		}
+		if out.String() != test.stdout {
+			t.Errorf("%s: expected output %q got %q", test.input, test.stdout, out.String())
+		}
+	}
+}
+
+func TestToNumber(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`轉數字（"42"）`, "42"},
+		{`轉數字（"-7"）`, "-7"},
+		{`轉數字（"１２"）`, "12"},
+		{`轉數字（"－１２"）`, "-12"},
+		{`轉數字（"二百五十"）`, "250"},
+		{`轉數字（"負三"）`, "-3"},
+		{`轉數字（5）`, "5"},
+	}
+	for _, test := range tests {
+		output := testEval(t, test.input)
+		if output.Inspect() != test.expected {
+			t.Errorf("%s: expected %s got %s", test.input, test.expected, output.Inspect())
+		}
+	}
+}
+
+func TestUnicodeString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`有幾長（"你好"）`, "2"},
+		{`有幾長（"hello 世界"）`, "8"},
+		{`有幾長（"😀👍🏽"）`, "3"},
+		{`有幾長（中文數字（2024））`, "6"},
+		{`"你好世界"[3]`, "界"},
+		{`"a😀b"[1]`, "😀"},
+		{`"a😀b"[2]`, "b"},
+		{`塞 "" 入 out; 逐個 c 喺 "廣東話😀" 度，就「 塞 c + "," + out 入 out。 」; out`, "😀,話,東,廣,"},
+		{`"一" < "二"`, "true"},
+		{`"啊" < "😀"`, "true"},
+		{`"b" > "a😀"`, "true"},
+		{`"你好" == "你" + "好"`, "true"},
+		{`有幾長（"a\nb"）`, "3"},
+		{`"\u{4f60}" == "你"`, "true"},
+		{"有幾長（『一\n二』）", "3"},
+		{`『\n』`, `\n`},
+	}
+	for _, test := range tests {
+		output := testEval(t, test.input)
+		if output.Inspect() != test.expected {
+			t.Errorf("%s: expected %s got %s", test.input, test.expected, output.Inspect())
+		}
+	}
+
+	for _, input := range []string{`"你好"[2]`, `"😀"[1]`, `"你好"[-1]`} {
+		err, ok := testEval(t, input).(*object.Error)
+		if !ok || err.Message != "index error" {
+			t.Errorf("%s: expected index error", input)
+		}
+	}
+}
+
+func TestInterpolation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`塞 "阿明" 入 名; “你好，{名}！”`, "你好，阿明！"},
+		{`塞 3 入 x; "{x} + {x} = {x + x}"`, "3 + 3 = 6"},
+		{`"{1.5} {啱} {[1, "a"]} {{"k": 2}}"`, `1.5 true [1, a] {k: 2}`},
+		{`"{有幾長（"你好"）}"`, "2"},
+		{`"{"a" + "{1}"}"`, "a1"},
+		{`"\{x\}"`, "{x}"},
+		{`『{x}』`, "{x}"},
+		{`塞 0 入 i; 當 （i 細過 3） 時，就「 塞 i+1 入 i。 」; "i={i}"`, "i=3"},
+	}
+	for _, test := range tests {
+		output := testEval(t, test.input)
+		str, ok := output.(*object.String)
+		if !ok {
+			t.Errorf("%s: expected object.String got %T (%+v)", test.input, output, output)
+			continue
+		}
+		if str.Value != test.expected {
+			t.Errorf("%s: expected %s got %s", test.input, test.expected, str.Value)
+		}
+	}
+
+	err, ok := testEval(t, `"a{1 / 0}b"`).(*object.Error)
+	if !ok || err.Pos.Column != 4 {
+		t.Errorf("expected error at column 4 got %+v", err)
+	}
+}
+
+func TestStringBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`分割（"a,b,,c"，","）`, "[a, b, , c]"},
+		{`分割（"  你好  世界 "）`, "[你好, 世界]"},
+		{`分割（"你好😀"，""）`, "[你, 好, 😀]"},
+		{`split（"1-2"，"-"）`, "[1, 2]"},
+		{`連埋（["a", "b", "c"]，"、"）`, "a、b、c"},
+		{`連埋（[1, 2.5, 啱]）`, "12.5true"},
+		{`join（[]，","）`, ""},
+		{`去空白（"  你好 \n"）`, "你好"},
+		{`大楷（"Hello 你好"）`, "HELLO 你好"},
+		{`lower（"ÀBC"）`, "àbc"},
+		{`包含（"廣東話"，"東"）`, "true"},
+		{`contains（"廣東話"，"國"）`, "false"},
+		{`搵位置（"😀你好世界"，"世界"）`, "3"},
+		{`index_of（"abc"，"a"）`, "0"},
+		{`搵位置（"abc"，"z"）`, "-1"},
+		{`替換（"一二一"，"一"，"三"）`, "三二三"},
+		{`切（"你好世界"，1，3）`, "好世"},
+		{`切（"你好😀"，2）`, "😀"},
+		{`substring（"abc"，3）`, ""},
+		{`重複（"哈"，3）`, "哈哈哈"},
+		{`repeat（"ab"，0）`, ""},
+		{`開頭係（"廣東話"，"廣東"）`, "true"},
+		{`結尾係（"廣東話"，"東"）`, "false"},
+		{`ends_with（"a.txt"，".txt"）`, "true"},
+		{`to_int（"－１２"）`, "-12"},
+		{`轉文字（12） + "3"`, "123"},
+		{`to_str（[1, "a"]）`, "[1, a]"},
+		{`to_str（"a"）`, "a"},
+		{`說明（split）`, "分割（s，sep）array of the parts of s between sep, or between spaces without sep"},
+	}
+	for _, test := range tests {
+		output := testEval(t, test.input)
+		got := output.Inspect()
+		if str, ok := output.(*object.String); ok {
+			got = str.Value
+		}
+		if got != test.expected {
+			t.Errorf("%s: expected %s got %s", test.input, test.expected, got)
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{`分割（1，","）`, "expected STRING_OBJ got INT_OBJ"},
+		{`連埋（"abc"）`, "expected ARRAY_OBJ got STRING_OBJ"},
+		{`連埋（[1]，2）`, "expected STRING_OBJ got INT_OBJ"},
+		{`包含（"a"，[1]）`, "expected STRING_OBJ got ARRAY_OBJ"},
+		{`切（"abc"，2，1）`, "slice 2 to 1 out of range for length 3"},
+		{`切（"你好"，0，3）`, "slice 0 to 3 out of range for length 2"},
+		{`substring（"abc"，-1）`, "slice -1 to 3 out of range for length 3"},
+		{`切（"abc"，"1"）`, "expected INT_OBJ got STRING_OBJ"},
+		{`重複（"a"，-1）`, "cannot repeat -1 times"},
+		{`重複（"ab"，9223372036854775807）`, "cannot repeat 9223372036854775807 times"},
+		{`重複（"你"，3074457345618258603）`, "cannot repeat 3074457345618258603 times"},
+		{`upper（）`, "upper expected 1 args got 0"},
+	}
+	for _, test := range errors {
+		err, ok := testEval(t, test.input).(*object.Error)
+		if !ok {
+			t.Errorf("%s: expected error", test.input)
+			continue
+		}
+		if err.Description != test.expected {
+			t.Errorf("%s: expected %q got %q", test.input, test.expected, err.Description)
+		}
+	}
+}
+
+func TestArrayBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`聽到 double（x） 嘅話，就「 俾我 x * 2。 」; 變晒（【1，2，3】，double）`, "[2, 4, 6]"},
+		{`map（["a", "你好"]，有幾長）`, "[1, 2]"},
+		{`變晒（[]，有幾長）`, "[]"},
+		{`揀（[1, 2, 3, 4]，聽到（x） 嘅話，就「 俾我 x 大過 2。 」）`, "[3, 4]"},
+		{`filter（[1, 2, 3]，聽到（x） 嘅話，就「 如果 （x 係 2） 嘅話，就「 俾我 錯。 」; 俾我 啱。 」）`, "[1, 3]"},
+		{`累計（[1, 2, 3, 4]，聽到（a，b） 嘅話，就「 俾我 a + b。 」）`, "10"},
+		{`reduce（["a", "b"]，聽到（a，b） 嘅話，就「 俾我 b + a。 」，"!"）`, "ba!"},
+		{`累計（[]，聽到（a，b） 嘅話，就「 俾我 a + b。 」，0）`, "0"},
+		{`搵（[1, 5, 8]，聽到（x） 嘅話，就「 俾我 x 大過 4。 」）`, "5"},
+		{`find（[1]，聽到（x） 嘅話，就「 俾我 x 大過 4。 」）`, "NULL"},
+		{`任何（[1, 5]，聽到（x） 嘅話，就「 俾我 x 大過 4。 」）`, "true"},
+		{`any（[]，聽到（x） 嘅話，就「 俾我 啱。 」）`, "false"},
+		{`全部（[1, 5]，聽到（x） 嘅話，就「 俾我 x 大過 4。 」）`, "false"},
+		{`all（[]，聽到（x） 嘅話，就「 俾我 錯。 」）`, "true"},
+		{`排序（[3, 1.5, 2]）`, "[1.5, 2, 3]"},
+		{`sort（["b", "你", "a"]）`, "[a, b, 你]"},
+		{`排序（["aaa", "b", "cc"]，聽到（a，b） 嘅話，就「 俾我 有幾長（a） 細過 有幾長（b）。 」）`, "[b, cc, aaa]"},
+		{`sort（[[2, "a"], [1, "b"], [2, "c"]]，聽到（a，b） 嘅話，就「 俾我 a[0] 細過 b[0]。 」）`, "[[1, b], [2, a], [2, c]]"},
+		{`塞 [3, 1] 入 a; 排序（a）; a`, "[3, 1]"},
+		{`倒轉（[1, 2, 3]）`, "[3, 2, 1]"},
+		{`reverse（[]）`, "[]"},
+		{`切（[1, 2, 3, 4]，1，3）`, "[2, 3]"},
+		{`slice（[1, 2, 3]，1）`, "[2, 3]"},
+		{`slice（"你好世界"，2）`, "世界"},
+		{`駁埋（[1]，[]，[2, 3]）`, "[1, 2, 3]"},
+		{`concat（[1]）`, "[1]"},
+		{`變晒（[[1], [2, 3]]，聽到（x） 嘅話，就「 俾我 累計（x，聽到（a，b） 嘅話，就「 俾我 a + b。 」）。 」）`, "[1, 5]"},
+	}
+	for _, test := range tests {
+		output := testEval(t, test.input)
+		if output.Inspect() != test.expected {
+			t.Errorf("%s: expected %s got %s", test.input, test.expected, output.Inspect())
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{`變晒（"abc"，有幾長）`, "expected ARRAY_OBJ got STRING_OBJ"},
+		{`揀（範圍（3），有幾長）`, "expected ARRAY_OBJ got RANGE_OBJ"},
+		{`變晒（[1]，1）`, "expected FUNCTION_OBJ got INT_OBJ"},
+		{`變晒（[1]，聽到（a，b） 嘅話，就「 俾我 a。 」）`, "expected 2 args got 1"},
+		{`map（[1]，有幾長）`, "INT_OBJ"},
+		{`累計（[]，聽到（a，b） 嘅話，就「 俾我 a。 」）`, "累計 of an empty array needs a start value"},
+		{`排序（[1, "a"]）`, "*object.String (&{Value:a}) 細過 *object.Integer (&{Value:1})"},
+		{`切（[1, 2]，0，3）`, "slice 0 to 3 out of range for length 2"},
+		{`切（1，0）`, "expected ARRAY_OBJ or STRING_OBJ got INT_OBJ"},
+		{`駁埋（[1]，2）`, "expected ARRAY_OBJ got INT_OBJ"},
+		{`揀（[1, 0]，聽到（x） 嘅話，就「 俾我 1 / x。 」）`, "*object.Integer (&{Value:1}) / *object.Integer (&{Value:0})"},
+	}
+	for _, test := range errors {
+		err, ok := testEval(t, test.input).(*object.Error)
+		if !ok {
+			t.Errorf("%s: expected error", test.input)
+			continue
+		}
+		if err.Description != test.expected {
+			t.Errorf("%s: expected %q got %q", test.input, test.expected, err.Description)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package evaluator
+
+import (
+	"cantolang/ast"
+	"cantolang/lexer"
+	"cantolang/object"
+	"cantolang/parser"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func EvalImportStatement(statement *ast.ImportStatement, env *object.Environment) object.Object {
+	path, err := resolveImport(statement.Path.Value, statement.Token.Pos.File, env.Runtime.SearchPath)
+	if err != nil {
+		return err
+	}
+	module, ok := env.Runtime.Modules[path]
+	if !ok {
+		result := loadModule(path, env.Runtime)
+		if err, ok := result.(*object.Error); ok {
+			err.Stack = append(err.Stack, object.Frame{Function: "<引入 import " + statement.Path.Value + ">", Pos: statement.Pos()})
+			return err
+		}
+		module = result.(*object.Module)
+	}
+	env.Scope().Set(statement.Name.Token.TokenLiteral, module)
+	return object.NULL
+}
+
+// resolveImport finds the file to import, relative to the importing file
+// first and then to each directory of the search path. The .txt extension
+// may be left out.
+func resolveImport(path string, importer string, searchPath []string) (string, *object.Error) {
+	candidates := []string{path}
+	if filepath.Ext(path) == "" {
+		candidates = append(candidates, path+".txt")
+	}
+	dirs := []string{""}
+	if !filepath.IsAbs(path) {
+		dirs = append([]string{filepath.Dir(importer)}, searchPath...)
+	}
+	for _, dir := range dirs {
+		for _, candidate := range candidates {
+			full := filepath.Join(dir, candidate)
+			if info, err := os.Stat(full); err == nil && !info.IsDir() {
+				abs, err := filepath.Abs(full)
+				if err != nil {
+					return "", Errorf("import error", "%s", err)
+				}
+				return abs, nil
+			}
+		}
+	}
+	return "", Errorf("import error", "cannot find module %s", path)
+}
+
+// loadModule evaluates the file at path in a fresh global environment that
+// shares rt, and caches the resulting module.
+func loadModule(path string, rt *object.Runtime) object.Object {
+	for i, loading := range rt.Loading {
+		if loading == path {
+			cycle := append(append([]string{}, rt.Loading[i:]...), path)
+			for j := range cycle {
+				cycle[j] = filepath.Base(cycle[j])
+			}
+			return Errorf("import error", "import cycle %s", strings.Join(cycle, " -> "))
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Errorf("import error", "%s", err)
+	}
+	p := parser.New(lexer.NewFile(path, string(data)))
+	program := p.ParseProgram()
+	if len(p.Errors) > 0 {
+		return Errorf("import error", "%s", strings.Join(p.Errors, "\n"))
+	}
+
+	rt.Loading = append(rt.Loading, path)
+	defer func() {
+		rt.Loading = rt.Loading[:len(rt.Loading)-1]
+	}()
+	env := &object.Environment{Bindings: make(map[string]object.Object), Runtime: rt}
+	result := Eval(program, env)
+	if isError(result) {
+		return result
+	}
+	name := filepath.Base(path)
+	module := &object.Module{Name: strings.TrimSuffix(name, filepath.Ext(name)), Path: path, Env: env}
+	rt.Modules[path] = module
+	return module
+}
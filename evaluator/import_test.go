@@ -0,0 +1,156 @@
+package evaluator
+
+import (
+	"cantolang/lexer"
+	"cantolang/object"
+	"cantolang/parser"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates files relative to a temporary directory and returns it
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func testEvalFile(t *testing.T, path string, env *object.Environment) object.Object {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := parser.New(lexer.NewFile(path, string(data)))
+	program := p.ParseProgram()
+	if len(p.Errors) > 0 {
+		t.Fatalf("parser errors: %v", p.Errors)
+	}
+	return Eval(program, env)
+}
+
+func TestImport(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"數學.txt": `
+		塞 3 入 數。
+		聽到 平方（x） 嘅話，就「 俾我 x * x。 」
+		`,
+		"lib/工具.txt": `
+		引入 “../數學.txt” 做 m。
+		聽到 立方（x） 嘅話，就「 俾我 m.平方（x） * x。 」
+		`,
+		"search/外部.txt": `塞 “outside” 入 名。`,
+	})
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`引入 “數學.txt” 做 m。 m.平方（m.數）`, "9"},
+		{`引入 “數學” 做 m。 m 嘅 平方（4）`, "16"},
+		{`引入 “數學.txt”。 數學.數`, "3"},
+		{`引入 “lib/工具.txt” 做 工具。 工具.立方（2）`, "8"},
+		{`引入 “外部” 做 x。 x.名`, "outside"},
+		{`引入 “數學” 做 m。 m`, "模組(數學)"},
+		{`引入 “數學” 做 m。 m.平方（m.數）; 數`, "NULL"},
+	}
+	for _, test := range tests {
+		main := filepath.Join(dir, "main.txt")
+		if err := os.WriteFile(main, []byte(test.input), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		env := object.NewEnvironment(nil)
+		env.Runtime.SearchPath = []string{filepath.Join(dir, "search")}
+		output := testEvalFile(t, main, env)
+		if output.Inspect() != test.expected {
+			t.Errorf("%s: expected %s got %s", test.input, test.expected, output.Inspect())
+		}
+	}
+}
+
+func TestImportCache(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.txt":    `塞 {} 入 items。`,
+		"main.txt": `引入 “a” 做 x。 引入 “./a.txt” 做 y。 塞 1 入 x.items【“k”】。 y.items【“k”】`,
+	})
+	env := object.NewEnvironment(nil)
+	output := testEvalFile(t, filepath.Join(dir, "main.txt"), env)
+	if output.Inspect() != "1" {
+		t.Fatalf("expected 1 got %s", output.Inspect())
+	}
+	if len(env.Runtime.Modules) != 1 {
+		t.Errorf("expected 1 cached module got %d", len(env.Runtime.Modules))
+	}
+	x, _ := env.Get("x")
+	y, _ := env.Get("y")
+	if x != y {
+		t.Errorf("expected both imports to share one module got %s and %s", x.Inspect(), y.Inspect())
+	}
+}
+
+func TestImportError(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.txt":   `引入 “b” 做 b。`,
+		"b.txt":   `引入 “c” 做 c。`,
+		"c.txt":   `引入 “a” 做 a。`,
+		"bad.txt": `塞 1 入`,
+		"err.txt": `
+		塞 1 入 x。
+		塞 x + 啱 入 y。`,
+		"self.txt": `引入 “self” 做 me。`,
+	})
+	tests := []struct {
+		input       string
+		message     string
+		description string
+	}{
+		{`引入 “missing” 做 m`, "import error", "cannot find module missing"},
+		{`引入 “a” 做 a`, "import error", "import cycle a.txt -> b.txt -> c.txt -> a.txt"},
+		{`引入 “self”`, "import error", "import cycle self.txt -> self.txt"},
+		{`引入 “bad” 做 m`, "import error", "bad.txt:1:6"},
+		{`引入 “err” 做 m`, "type mismatch", ""},
+		{`引入 “err” 做 m。 m.z`, "type mismatch", ""},
+		{`塞 1 入 n。 n.x`, "type error", ""},
+	}
+	for _, test := range tests {
+		main := filepath.Join(dir, "main.txt")
+		if err := os.WriteFile(main, []byte(test.input), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		env := object.NewEnvironment(nil)
+		output := testEvalFile(t, main, env)
+		err, ok := output.(*object.Error)
+		if !ok {
+			t.Fatalf("%s: expected object.Error got %s", test.input, output.Inspect())
+		}
+		if err.Message != test.message {
+			t.Errorf("%s: expected message %s got %s", test.input, test.message, err.Message)
+		}
+		if !strings.Contains(err.Description, test.description) {
+			t.Errorf("%s: expected description containing %s got %s", test.input, test.description, err.Description)
+		}
+		if len(env.Runtime.Loading) != 0 {
+			t.Errorf("%s: expected no modules loading got %v", test.input, env.Runtime.Loading)
+		}
+	}
+
+	// the traceback goes through the import
+	main := filepath.Join(dir, "main.txt")
+	os.WriteFile(main, []byte(`引入 “err” 做 m`), 0o644)
+	err := testEvalFile(t, main, object.NewEnvironment(nil)).(*object.Error)
+	if filepath.Base(err.Pos.File) != "err.txt" || err.Pos.Line != 3 {
+		t.Errorf("expected error in err.txt:3 got %s", err.Pos)
+	}
+	if len(err.Stack) != 1 || err.Stack[0].Pos.File != main {
+		t.Errorf("expected import frame in %s got %+v", main, err.Stack)
+	}
+}
@@ -0,0 +1,224 @@
+package evaluator
+
+import (
+	"cantolang/object"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// stringBuiltins work on strings one character at a time, not byte by byte
+var stringBuiltins = map[string]*object.BuiltIn{
+	"分割": {
+		MinArgs: 1,
+		MaxArgs: 2,
+		Doc:     "分割（s，sep）array of the parts of s between sep, or between spaces without sep",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			strs, err := stringArgs(args...)
+			if err != nil {
+				return err
+			}
+			var parts []string
+			if len(strs) == 1 {
+				parts = strings.Fields(strs[0])
+			} else {
+				parts = strings.Split(strs[0], strs[1])
+			}
+			items := make([]object.Object, len(parts))
+			for i, part := range parts {
+				items[i] = &object.String{Value: part}
+			}
+			return &object.Array{Items: items}
+		},
+	},
+	"連埋": {
+		MinArgs: 1,
+		MaxArgs: 2,
+		Doc:     "連埋（arr，sep）join the items of an array into a string with sep between them",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			arr, ok := args[0].(*object.Array)
+			if !ok {
+				return Errorf("invalid argument type", "expected %s got %s", object.ARRAY_OBJ, args[0].Type())
+			}
+			sep := ""
+			if len(args) == 2 {
+				strs, err := stringArgs(args[1])
+				if err != nil {
+					return err
+				}
+				sep = strs[0]
+			}
+			parts := make([]string, len(arr.Items))
+			for i, item := range arr.Items {
+				parts[i] = toString(item)
+			}
+			return &object.String{Value: strings.Join(parts, sep)}
+		},
+	},
+	"去空白": {
+		MinArgs: 1,
+		MaxArgs: 1,
+		Doc:     "去空白（s）s without spaces at the start and end",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			strs, err := stringArgs(args...)
+			if err != nil {
+				return err
+			}
+			return &object.String{Value: strings.TrimSpace(strs[0])}
+		},
+	},
+	"大楷": {
+		MinArgs: 1,
+		MaxArgs: 1,
+		Doc:     "大楷（s）s in upper case",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			strs, err := stringArgs(args...)
+			if err != nil {
+				return err
+			}
+			return &object.String{Value: strings.ToUpper(strs[0])}
+		},
+	},
+	"細楷": {
+		MinArgs: 1,
+		MaxArgs: 1,
+		Doc:     "細楷（s）s in lower case",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			strs, err := stringArgs(args...)
+			if err != nil {
+				return err
+			}
+			return &object.String{Value: strings.ToLower(strs[0])}
+		},
+	},
+	"包含": {
+		MinArgs: 2,
+		MaxArgs: 2,
+		Doc:     "包含（s，sub）whether s contains sub",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			strs, err := stringArgs(args...)
+			if err != nil {
+				return err
+			}
+			return getBoolObj(strings.Contains(strs[0], strs[1]))
+		},
+	},
+	"搵位置": {
+		MinArgs: 2,
+		MaxArgs: 2,
+		Doc:     "搵位置（s，sub）index of the first sub in s, -1 if there is none",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			strs, err := stringArgs(args...)
+			if err != nil {
+				return err
+			}
+			i := strings.Index(strs[0], strs[1])
+			if i > 0 {
+				i = utf8.RuneCountInString(strs[0][:i])
+			}
+			return &object.Integer{Value: i}
+		},
+	},
+	"替換": {
+		MinArgs: 3,
+		MaxArgs: 3,
+		Doc:     "替換（s，old，new）s with every old replaced by new",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			strs, err := stringArgs(args...)
+			if err != nil {
+				return err
+			}
+			return &object.String{Value: strings.ReplaceAll(strs[0], strs[1], strs[2])}
+		},
+	},
+	"重複": {
+		MinArgs: 2,
+		MaxArgs: 2,
+		Doc:     "重複（s，n）s repeated n times",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			strs, err := stringArgs(args[0])
+			if err != nil {
+				return err
+			}
+			n, ok := args[1].(*object.Integer)
+			if !ok {
+				return Errorf("invalid argument type", "expected %s got %s", object.INT_OBJ, args[1].Type())
+			}
+			if n.Value < 0 || (len(strs[0]) > 0 && n.Value > math.MaxInt/len(strs[0])) {
+				return Errorf("invalid argument", "cannot repeat %d times", n.Value)
+			}
+			return &object.String{Value: strings.Repeat(strs[0], n.Value)}
+		},
+	},
+	"開頭係": {
+		MinArgs: 2,
+		MaxArgs: 2,
+		Doc:     "開頭係（s，prefix）whether s starts with prefix",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			strs, err := stringArgs(args...)
+			if err != nil {
+				return err
+			}
+			return getBoolObj(strings.HasPrefix(strs[0], strs[1]))
+		},
+	},
+	"結尾係": {
+		MinArgs: 2,
+		MaxArgs: 2,
+		Doc:     "結尾係（s，suffix）whether s ends with suffix",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			strs, err := stringArgs(args...)
+			if err != nil {
+				return err
+			}
+			return getBoolObj(strings.HasSuffix(strs[0], strs[1]))
+		},
+	},
+	"轉文字": {
+		MinArgs: 1,
+		MaxArgs: 1,
+		Doc:     "轉文字（x）x as a string, the way 講 prints it",
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return &object.String{Value: toString(args[0])}
+		},
+	},
+}
+
+// stringAliases are English names for the string builtins
+var stringAliases = map[string]string{
+	"split":       "分割",
+	"join":        "連埋",
+	"trim":        "去空白",
+	"upper":       "大楷",
+	"lower":       "細楷",
+	"contains":    "包含",
+	"index_of":    "搵位置",
+	"replace":     "替換",
+	"substring":   "切",
+	"repeat":      "重複",
+	"starts_with": "開頭係",
+	"ends_with":   "結尾係",
+	"to_int":      "轉數字",
+	"to_str":      "轉文字",
+}
+
+// stringArgs returns the values of args, or an error if any is not a string
+func stringArgs(args ...object.Object) ([]string, *object.Error) {
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		str, ok := arg.(*object.String)
+		if !ok {
+			return nil, Errorf("invalid argument type", "expected %s got %s", object.STRING_OBJ, arg.Type())
+		}
+		strs[i] = str.Value
+	}
+	return strs, nil
+}
+
+// toString is how a value is written inside a string
+func toString(obj object.Object) string {
+	if str, ok := obj.(*object.String); ok {
+		return str.Value
+	}
+	return obj.Inspect()
+}
@@ -1,7 +1,12 @@
 package lexer
 
 import (
+	"cantolang/numeral"
 	token "cantolang/token"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
 type Lexer struct {
@@ -10,23 +15,57 @@ type Lexer struct {
 	char       rune
 	peekChar   rune
 	quotePairs map[rune]rune
+
+	file   string
+	line   int
+	column int
+	offset int
 }
 
 func New(input string) *Lexer {
+	return NewFile("", input)
+}
+
+// NewAt creates a lexer for input found at pos, such as an expression inside a
+// string.
+func NewAt(pos token.Position, input string) *Lexer {
+	l := NewFile(pos.File, input)
+	l.line = pos.Line
+	l.column = pos.Column
+	l.offset = pos.Offset
+	return l
+}
+
+// NewFile creates a lexer whose token positions are reported against filename.
+func NewFile(filename string, input string) *Lexer {
 	l := &Lexer{
-		input: []rune(input),
+		input:  []rune(input),
+		file:   filename,
+		line:   1,
+		column: 1,
 	}
 	l.pos = -1
 	l.quotePairs = map[rune]rune{
 		'"': '"',
 		'“': '”',
 		'”': 0,
+		'『': '』',
+		'』': 0,
 	}
 	l.advance()
 	return l
 }
 
 func (l *Lexer) advance() {
+	if l.pos >= 0 && l.pos < len(l.input) {
+		l.offset += utf8.RuneLen(l.char)
+		if l.char == '\n' {
+			l.line++
+			l.column = 1
+		} else {
+			l.column++
+		}
+	}
 	l.pos++
 	if l.pos < len(l.input) {
 		l.char = l.input[l.pos]
@@ -49,24 +88,167 @@ func (l *Lexer) readIdentifier() string {
 	return result
 }
 
-func (l *Lexer) readString(endChar rune) string {
+// escapes are the characters after \ in a string and what they stand for
+var escapes = map[rune]rune{
+	'n':  '\n',
+	't':  '\t',
+	'r':  '\r',
+	'\\': '\\',
+	'"':  '"',
+	'“':  '“',
+	'”':  '”',
+	'{':  '{',
+	'}':  '}',
+}
+
+// readString reads a string up to endChar, which may be on a later line,
+// replacing escape sequences and splitting out {expression} parts. It returns
+// an error message if the string is invalid or never closed.
+func (l *Lexer) readString(endChar rune) (string, []token.TemplatePart, string) {
 	l.advance()
-	result := ""
-	for l.char != endChar && l.char != 0 {
-		result += string(l.char)
+	result := strings.Builder{}
+	parts := []token.TemplatePart{}
+	textPos := l.position()
+	errMsg := ""
+	for l.char != endChar {
+		if l.char == 0 {
+			return result.String(), nil, "unterminated string"
+		}
+		if l.char == '{' {
+			if result.Len() > 0 {
+				parts = append(parts, token.TemplatePart{Value: result.String(), Pos: textPos})
+				result.Reset()
+			}
+			l.advance()
+			exprPos := l.position()
+			expr, ok := l.readInterpolation()
+			if !ok {
+				return "", nil, "unterminated string"
+			}
+			if strings.TrimSpace(expr) == "" && errMsg == "" {
+				errMsg = "empty {} in string, use \\{ for a brace"
+			}
+			parts = append(parts, token.TemplatePart{Value: expr, IsExpr: true, Pos: exprPos})
+			textPos = l.position()
+			continue
+		}
+		if l.char == '}' {
+			if errMsg == "" {
+				errMsg = "unmatched } in string, use \\} for a brace"
+			}
+			l.advance()
+			continue
+		}
+		if l.char != '\\' {
+			result.WriteRune(l.char)
+			l.advance()
+			continue
+		}
+		l.advance()
+		if l.char == 'u' && l.peekChar == '{' {
+			r, ok := l.readCodePoint()
+			if !ok && errMsg == "" {
+				errMsg = "invalid unicode escape"
+			}
+			result.WriteRune(r)
+			continue
+		}
+		escaped, ok := escapes[l.char]
+		if !ok {
+			if l.char == 0 || l.char == '\n' {
+				continue
+			}
+			if errMsg == "" {
+				errMsg = fmt.Sprintf("invalid escape \\%c", l.char)
+			}
+			escaped = l.char
+		}
+		result.WriteRune(escaped)
 		l.advance()
 	}
-	if l.char == endChar {
+	l.advance()
+	if len(parts) == 0 {
+		return result.String(), nil, errMsg
+	}
+	if result.Len() > 0 {
+		parts = append(parts, token.TemplatePart{Value: result.String(), Pos: textPos})
+	}
+	return "", parts, errMsg
+}
+
+// readInterpolation reads the source of an expression up to the } that closes
+// it, skipping over braces and strings inside it
+func (l *Lexer) readInterpolation() (string, bool) {
+	start := l.pos
+	depth := 0
+	for l.char != '}' || depth > 0 {
+		switch l.char {
+		case 0:
+			return "", false
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '"', '“':
+			end := l.quotePairs[l.char]
+			l.advance()
+			for l.char != end {
+				if l.char == 0 {
+					return "", false
+				}
+				if l.char == '\\' {
+					l.advance()
+				}
+				l.advance()
+			}
+		}
 		l.advance()
 	}
-	return result
+	expr := string(l.input[start:l.pos])
+	l.advance()
+	return expr, true
+}
+
+// readCodePoint reads u{hex} after a \ in a string
+func (l *Lexer) readCodePoint() (rune, bool) {
+	l.advance()
+	l.advance()
+	hex := ""
+	for l.char != '}' && l.char != 0 && l.char != '\n' && l.char != '"' && l.char != '”' {
+		hex += string(l.char)
+		l.advance()
+	}
+	if l.char != '}' {
+		return utf8.RuneError, false
+	}
+	l.advance()
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) > 6 || !utf8.ValidRune(rune(n)) {
+		return utf8.RuneError, false
+	}
+	return rune(n), true
+}
+
+// readRawString reads a 『』 string as it is, it can span lines
+func (l *Lexer) readRawString(endChar rune) (string, string) {
+	l.advance()
+	result := strings.Builder{}
+	for l.char != endChar {
+		if l.char == 0 {
+			return result.String(), "unterminated string"
+		}
+		result.WriteRune(l.char)
+		l.advance()
+	}
+	l.advance()
+	return result.String(), ""
 }
 
 func isAllowedInIdent(char rune) bool {
 	if char == 0 {
 		return false
 	}
-	restricted := []rune(" \n1234567890!@#$%^&<>=")
+	restricted := []rune(" \n1234567890１２３４５６７８９０!@#$%^&<>=")
 	for _, c := range restricted {
 		if c == char {
 			return false
@@ -80,17 +262,47 @@ func isAllowedInIdent(char rune) bool {
 
 func (l *Lexer) readNumber() string {
 	result := ""
-	for l.char >= '0' && l.char <= '9' {
+	for isDigit(l.char) {
 		result += string(l.char)
 		l.advance()
 	}
 	return result
 }
 
+func isDigit(char rune) bool {
+	return numeral.IsDigit(char)
+}
+
+func (l *Lexer) readChineseNumber() string {
+	result := ""
+	for numeral.IsChinese(l.char) {
+		result += string(l.char)
+		l.advance()
+	}
+	return result
+}
+
+// isDecimalPoint reports whether the lexer is at the point of a decimal literal
+func (l *Lexer) isDecimalPoint() bool {
+	return (l.char == '.' || l.char == '．') && isDigit(l.peekChar)
+}
+
+func (l *Lexer) position() token.Position {
+	return token.Position{File: l.file, Line: l.line, Column: l.column, Offset: l.offset}
+}
+
 func (l *Lexer) ReadToken() token.Token {
 	for l.char == ' ' || l.char == '\n' || l.char == '\r' || l.char == '\t' {
 		l.advance()
 	}
+	start := l.position()
+	t := l.readToken()
+	t.Pos = start
+	t.End = l.position()
+	return t
+}
+
+func (l *Lexer) readToken() token.Token {
 	if l.char == 0 {
 		return token.Token{
 			TokenType:    token.EOF,
@@ -101,7 +313,7 @@ func (l *Lexer) ReadToken() token.Token {
 
 	// check for comment
 	if l.char == '/' && l.peekChar == '/' {
-		for l.char != '\n' {
+		for l.char != '\n' && l.char != 0 {
 			l.advance()
 		}
 		t.TokenType = token.COMMENT
@@ -115,22 +327,73 @@ func (l *Lexer) ReadToken() token.Token {
 		l.advance()
 		return t
 	}
+	// check for comparison operator
+	if op := token.LookUpOperator(string(l.char) + string(l.peekChar)); op != token.TEMP_NOT_SYMBOL {
+		t.TokenType = op
+		t.TokenLiteral = string(l.char) + string(l.peekChar)
+		l.advance()
+		l.advance()
+		return t
+	}
+	if op := token.LookUpOperator(string(l.char)); op != token.TEMP_NOT_SYMBOL {
+		t.TokenType = op
+		t.TokenLiteral = string(l.char)
+		l.advance()
+		return t
+	}
 	// check for number
-	if l.char >= '0' && l.char <= '9' {
+	if isDigit(l.char) {
 		t.TokenType = token.NUMBER
 		t.TokenLiteral = l.readNumber()
+		if l.isDecimalPoint() {
+			t.TokenType = token.FLOAT
+			t.TokenLiteral += string(l.char)
+			l.advance()
+			t.TokenLiteral += l.readNumber()
+		}
 		return t
 	}
 	// check for string
 	matchingQuote, ok := l.quotePairs[l.char]
 	if ok {
 		if matchingQuote == 0 {
+			// a closing quote without an opening one
 			t.TokenType = token.INVALID
 			t.TokenLiteral = string(l.char)
+			l.advance()
 			return t
 		}
+		var errMsg string
 		t.TokenType = token.STRING
-		t.TokenLiteral = l.readString(matchingQuote)
+		if l.char == '『' {
+			t.TokenLiteral, errMsg = l.readRawString(matchingQuote)
+		} else {
+			start := l.pos
+			t.TokenLiteral, t.Parts, errMsg = l.readString(matchingQuote)
+			if t.Parts != nil {
+				t.TokenType = token.TEMPLATE
+				t.TokenLiteral = string(l.input[start:l.pos])
+			}
+		}
+		if errMsg != "" {
+			t.TokenType = token.ERROR
+			t.TokenLiteral = errMsg
+			t.Parts = nil
+		}
+		return t
+	}
+
+	// check for chinese number, unless it starts an identifier like 一齊
+	if numeral.IsChinese(l.char) {
+		n := l.readChineseNumber()
+		if !isAllowedInIdent(l.char) {
+			t.TokenType = token.NUMBER
+			t.TokenLiteral = n
+			return t
+		}
+		i := n + l.readIdentifier()
+		t.TokenType = token.LookUpIdent(i)
+		t.TokenLiteral = i
 		return t
 	}
 
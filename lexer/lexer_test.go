@@ -125,6 +125,67 @@ func TestIfElse(t *testing.T) {
 	}
 }
 
+func TestKeywords(t *testing.T) {
+	input := `」唔係就如果 （b） 」唔係就 如果 睇下（x）「 _ 就「」 其他 就「」 」 試下「」出事就（e） 引入 “數學.txt” 做 m。 m.平方 m 嘅 開方`
+
+	expectedTokens := []struct {
+		Type    string
+		Literal string
+	}{
+		{token.CLOSE_BRACE, "」"},
+		{token.ELSE_IF, "唔係就如果"},
+		{token.OPEN_PAREN, "（"},
+		{token.IDENTIFIER, "b"},
+		{token.CLOSE_PAREN, "）"},
+		{token.CLOSE_BRACE, "」"},
+		{token.ELSE, "唔係就"},
+		{token.IF, "如果"},
+		{token.MATCH, "睇下"},
+		{token.OPEN_PAREN, "（"},
+		{token.IDENTIFIER, "x"},
+		{token.CLOSE_PAREN, "）"},
+		{token.OPEN_BRACE, "「"},
+		{token.IDENTIFIER, "_"},
+		{token.THEN, "就"},
+		{token.OPEN_BRACE, "「"},
+		{token.CLOSE_BRACE, "」"},
+		{token.DEFAULT, "其他"},
+		{token.THEN, "就"},
+		{token.OPEN_BRACE, "「"},
+		{token.CLOSE_BRACE, "」"},
+		{token.CLOSE_BRACE, "」"},
+		{token.TRY, "試下"},
+		{token.OPEN_BRACE, "「"},
+		{token.CLOSE_BRACE, "」"},
+		{token.CATCH, "出事就"},
+		{token.OPEN_PAREN, "（"},
+		{token.IDENTIFIER, "e"},
+		{token.CLOSE_PAREN, "）"},
+		{token.IMPORT, "引入"},
+		{token.STRING, "數學.txt"},
+		{token.AS, "做"},
+		{token.IDENTIFIER, "m"},
+		{token.EOL, "。"},
+		{token.IDENTIFIER, "m"},
+		{token.DOT, "."},
+		{token.IDENTIFIER, "平方"},
+		{token.IDENTIFIER, "m"},
+		{token.DOT, "嘅"},
+		{token.IDENTIFIER, "開方"},
+		{token.EOF, ""},
+	}
+	l := New(input)
+	for i, exp := range expectedTokens {
+		got := l.ReadToken()
+		if got.TokenLiteral != exp.Literal {
+			t.Errorf("tests[%d] Expected literal '%s' got '%s'", i, exp.Literal, got.TokenLiteral)
+		}
+		if got.TokenType != exp.Type {
+			t.Errorf("tests[%d] Expected type '%s' got '%s'", i, exp.Type, got.TokenType)
+		}
+	}
+}
+
 func TestFunction(t *testing.T) {
 	input := `聽到 add（a，b） 嘅話，就「
 	    俾我 a 加 b。
@@ -200,6 +261,146 @@ func TestRegularSymbols(t *testing.T) {
 	}
 }
 
+func TestComparison(t *testing.T) {
+	input := `a 唔係咁 b 唔大過 c 唔細過 d == e != f < g > h <= i >= j<k=`
+
+	expectedTokens := []struct {
+		Type    string
+		Literal string
+	}{
+		{token.IDENTIFIER, "a"},
+		{token.NOT_EQUAL_TO, "唔係咁"},
+		{token.IDENTIFIER, "b"},
+		{token.LESS_EQUAL, "唔大過"},
+		{token.IDENTIFIER, "c"},
+		{token.GREATER_EQUAL, "唔細過"},
+		{token.IDENTIFIER, "d"},
+		{token.EQUAL_TO, "=="},
+		{token.IDENTIFIER, "e"},
+		{token.NOT_EQUAL_TO, "!="},
+		{token.IDENTIFIER, "f"},
+		{token.LESS_THAN, "<"},
+		{token.IDENTIFIER, "g"},
+		{token.GREATER_THAN, ">"},
+		{token.IDENTIFIER, "h"},
+		{token.LESS_EQUAL, "<="},
+		{token.IDENTIFIER, "i"},
+		{token.GREATER_EQUAL, ">="},
+		{token.IDENTIFIER, "j"},
+		{token.LESS_THAN, "<"},
+		{token.IDENTIFIER, "k"},
+		{token.INVALID, "="},
+		{token.EOF, ""},
+	}
+	l := New(input)
+	for i, exp := range expectedTokens {
+		got := l.ReadToken()
+		if got.TokenLiteral != exp.Literal {
+			t.Errorf("tests[%d] Expected literal '%s' got '%s'", i, exp.Literal, got.TokenLiteral)
+		}
+		if got.TokenType != exp.Type {
+			t.Errorf("tests[%d] Expected type '%s' got '%s'", i, exp.Type, got.TokenType)
+		}
+
+	}
+}
+
+func TestForEach(t *testing.T) {
+	input := `逐個 x 喺 arr 度，就「 停。 」`
+
+	expectedTokens := []struct {
+		Type    string
+		Literal string
+	}{
+		{token.FOREACH, "逐個"},
+		{token.IDENTIFIER, "x"},
+		{token.HAI, "喺"},
+		{token.IDENTIFIER, "arr"},
+		{token.DOU, "度"},
+		{token.COMMA, "，"},
+		{token.THEN, "就"},
+		{token.OPEN_BRACE, "「"},
+		{token.BREAK, "停"},
+		{token.EOL, "。"},
+		{token.CLOSE_BRACE, "」"},
+		{token.EOF, ""},
+	}
+	l := New(input)
+	for i, exp := range expectedTokens {
+		got := l.ReadToken()
+		if got.TokenLiteral != exp.Literal {
+			t.Errorf("tests[%d] Expected literal '%s' got '%s'", i, exp.Literal, got.TokenLiteral)
+		}
+		if got.TokenType != exp.Type {
+			t.Errorf("tests[%d] Expected type '%s' got '%s'", i, exp.Type, got.TokenType)
+		}
+	}
+}
+
+func TestFloat(t *testing.T) {
+	input := `3.14 0．5 12。 7.x`
+
+	expectedTokens := []struct {
+		Type    string
+		Literal string
+	}{
+		{token.FLOAT, "3.14"},
+		{token.FLOAT, "0．5"},
+		{token.NUMBER, "12"},
+		{token.EOL, "。"},
+		{token.NUMBER, "7"},
+		{token.DOT, "."},
+		{token.IDENTIFIER, "x"},
+		{token.EOF, ""},
+	}
+	l := New(input)
+	for i, exp := range expectedTokens {
+		got := l.ReadToken()
+		if got.TokenLiteral != exp.Literal {
+			t.Errorf("tests[%d] Expected literal '%s' got '%s'", i, exp.Literal, got.TokenLiteral)
+		}
+		if got.TokenType != exp.Type {
+			t.Errorf("tests[%d] Expected type '%s' got '%s'", i, exp.Type, got.TokenType)
+		}
+
+	}
+}
+
+func TestChineseNumber(t *testing.T) {
+	input := `塞 三十五 入 x。 廿一，１２ 一齊 第一 十）萬`
+
+	expectedTokens := []struct {
+		Type    string
+		Literal string
+	}{
+		{token.ASSIGN, "塞"},
+		{token.NUMBER, "三十五"},
+		{token.TO, "入"},
+		{token.IDENTIFIER, "x"},
+		{token.EOL, "。"},
+		{token.NUMBER, "廿一"},
+		{token.COMMA, "，"},
+		{token.NUMBER, "１２"},
+		{token.IDENTIFIER, "一齊"},
+		{token.IDENTIFIER, "第一"},
+		{token.NUMBER, "十"},
+		{token.CLOSE_PAREN, "）"},
+		{token.NUMBER, "萬"},
+		{token.EOF, ""},
+	}
+	l := New(input)
+	for i, exp := range expectedTokens {
+		got := l.ReadToken()
+		if got.TokenLiteral != exp.Literal {
+			t.Errorf("tests[%d] Expected literal '%s' got '%s'", i, exp.Literal, got.TokenLiteral)
+		}
+		if got.TokenType != exp.Type {
+			t.Errorf("tests[%d] Expected type '%s' got '%s'", i, exp.Type, got.TokenType)
+		}
+
+	}
+}
+
 func TestBool(t *testing.T) {
 	input := `
 	啱 錯。
@@ -239,7 +440,14 @@ func TestString(t *testing.T) {
 	input := `
 	"cantolang"
 	“hello world”
-`
+	"a\nb\tc\\d\"e" “\“引號\”” "\u{4f60}\u{597D}\u{1F600}"
+	『raw \n "quote"
+第二行』
+	"bad \q escape" "\u{110000}" "\u{4f60" "\u{zz}"
+	』
+	“兩
+行” "no end
+	『no end`
 
 	expectedTokens := []struct {
 		Type    string
@@ -247,6 +455,17 @@ func TestString(t *testing.T) {
 	}{
 		{token.STRING, "cantolang"},
 		{token.STRING, "hello world"},
+		{token.STRING, "a\nb\tc\\d\"e"},
+		{token.STRING, "“引號”"},
+		{token.STRING, "你好😀"},
+		{token.STRING, "raw \\n \"quote\"\n第二行"},
+		{token.ERROR, "invalid escape \\q"},
+		{token.ERROR, "invalid unicode escape"},
+		{token.ERROR, "invalid unicode escape"},
+		{token.ERROR, "invalid unicode escape"},
+		{token.INVALID, "』"},
+		{token.STRING, "兩\n行"},
+		{token.ERROR, "unterminated string"},
 		{token.EOF, ""},
 	}
 	l := New(input)
@@ -261,3 +480,121 @@ func TestString(t *testing.T) {
 
 	}
 }
+
+func TestTemplate(t *testing.T) {
+	input := `“你好，{名}！” "{a[1]}{ {"b": "}"}["b"] }" "\{x\}" "{}" "a}" "{x"`
+
+	expected := []struct {
+		Type    string
+		Literal string
+		Parts   []token.TemplatePart
+	}{
+		{token.TEMPLATE, "“你好，{名}！”", []token.TemplatePart{
+			{Value: "你好，"}, {Value: "名", IsExpr: true}, {Value: "！"},
+		}},
+		{token.TEMPLATE, `"{a[1]}{ {"b": "}"}["b"] }"`, []token.TemplatePart{
+			{Value: "a[1]", IsExpr: true}, {Value: ` {"b": "}"}["b"] `, IsExpr: true},
+		}},
+		{token.STRING, "{x}", nil},
+		{token.ERROR, "empty {} in string, use \\{ for a brace", nil},
+		{token.ERROR, "unmatched } in string, use \\} for a brace", nil},
+		{token.ERROR, "unterminated string", nil},
+		{token.EOF, "", nil},
+	}
+	l := New(input)
+	for i, exp := range expected {
+		got := l.ReadToken()
+		if got.TokenType != exp.Type {
+			t.Errorf("tests[%d] Expected type '%s' got '%s'", i, exp.Type, got.TokenType)
+		}
+		if got.TokenLiteral != exp.Literal {
+			t.Errorf("tests[%d] Expected literal '%s' got '%s'", i, exp.Literal, got.TokenLiteral)
+		}
+		if len(got.Parts) != len(exp.Parts) {
+			t.Errorf("tests[%d] Expected %d parts got %d", i, len(exp.Parts), len(got.Parts))
+			continue
+		}
+		for j, part := range exp.Parts {
+			if got.Parts[j].Value != part.Value || got.Parts[j].IsExpr != part.IsExpr {
+				t.Errorf("tests[%d] Expected part %+v got %+v", i, part, got.Parts[j])
+			}
+		}
+	}
+
+	// expression parts know where they are in the file
+	l = New(`塞 "x{y}" 入 z`)
+	l.ReadToken()
+	tok := l.ReadToken()
+	if len(tok.Parts) != 2 || tok.Parts[1].Pos.Line != 1 || tok.Parts[1].Pos.Column != 6 {
+		t.Errorf("Expected expression part at 1:6 got %+v", tok.Parts)
+	}
+}
+
+func TestStringPosition(t *testing.T) {
+	input := "講（『一\n二』，\"兩\n行\"）\n講（1，\"未完）\n講（1）"
+
+	expected := []struct {
+		Type   string
+		Line   int
+		Column int
+	}{
+		{token.IDENTIFIER, 1, 1},
+		{token.OPEN_PAREN, 1, 2},
+		{token.STRING, 1, 3},
+		{token.COMMA, 2, 3},
+		{token.STRING, 2, 4},
+		{token.CLOSE_PAREN, 3, 3},
+		{token.IDENTIFIER, 4, 1},
+		{token.OPEN_PAREN, 4, 2},
+		{token.NUMBER, 4, 3},
+		{token.COMMA, 4, 4},
+		{token.ERROR, 4, 5},
+		{token.EOF, 5, 5},
+	}
+	l := New(input)
+	for i, exp := range expected {
+		got := l.ReadToken()
+		if got.TokenType != exp.Type {
+			t.Errorf("tests[%d] Expected type '%s' got '%s'", i, exp.Type, got.TokenType)
+		}
+		if got.Pos.Line != exp.Line || got.Pos.Column != exp.Column {
+			t.Errorf("tests[%d] Expected position %d:%d got %d:%d", i, exp.Line, exp.Column, got.Pos.Line, got.Pos.Column)
+		}
+	}
+}
+
+func TestPosition(t *testing.T) {
+	input := "塞 12 入 i。\n講（“你好”）"
+
+	expected := []struct {
+		Type      string
+		Line      int
+		Column    int
+		Offset    int
+		EndColumn int
+	}{
+		{token.ASSIGN, 1, 1, 0, 2},
+		{token.NUMBER, 1, 3, 4, 5},
+		{token.TO, 1, 6, 7, 7},
+		{token.IDENTIFIER, 1, 8, 11, 9},
+		{token.EOL, 1, 9, 12, 10},
+		{token.IDENTIFIER, 2, 1, 16, 2},
+		{token.OPEN_PAREN, 2, 2, 19, 3},
+		{token.STRING, 2, 3, 22, 7},
+		{token.CLOSE_PAREN, 2, 7, 34, 8},
+		{token.EOF, 2, 8, 37, 8},
+	}
+	l := NewFile("test.txt", input)
+	for i, exp := range expected {
+		got := l.ReadToken()
+		if got.TokenType != exp.Type {
+			t.Errorf("tests[%d] Expected type '%s' got '%s'", i, exp.Type, got.TokenType)
+		}
+		if got.Pos.File != "test.txt" || got.Pos.Line != exp.Line || got.Pos.Column != exp.Column || got.Pos.Offset != exp.Offset {
+			t.Errorf("tests[%d] Expected position %d:%d (%d) got %+v", i, exp.Line, exp.Column, exp.Offset, got.Pos)
+		}
+		if got.End.Line != exp.Line || got.End.Column != exp.EndColumn {
+			t.Errorf("tests[%d] Expected end %d:%d got %+v", i, exp.Line, exp.EndColumn, got.End)
+		}
+	}
+}
@@ -1,13 +1,11 @@
 package main
 
 import (
-	"cantolang/evaluator"
-	"cantolang/lexer"
-	"cantolang/object"
-	"cantolang/parser"
+	"cantolang/cantolang"
 	"cantolang/repl"
 	"fmt"
 	"os"
+	"path/filepath"
 )
 
 func main() {
@@ -16,26 +14,10 @@ func main() {
 		repl.Start(os.Stdin, os.Stdout)
 	case 2:
 		filename := os.Args[1]
-		data, err := os.ReadFile(filename)
-		if err != nil {
-			fmt.Println("Error reading file:", err)
-			return
-		}
-		// Convert the byte slice to a string
-		input := string(data)
-		l := lexer.New(input)
-		p := parser.New(l)
-		program := p.ParseProgram()
-		if len(p.Errors) > 0 {
-			fmt.Printf("Got %d parser errors:\n", len(p.Errors))
-			for _, e := range p.Errors {
-				fmt.Println(e)
-			}
-			return
-		}
-		res := evaluator.Eval(program, object.NewEnvironment(nil))
-		if res.Type() == object.ERROR_OBJ {
-			fmt.Println(res)
+		interpreter := cantolang.New()
+		interpreter.SetSearchPath(filepath.SplitList(os.Getenv("CANTOLANG_PATH"))...)
+		if _, err := interpreter.RunFile(filename); err != nil {
+			interpreter.ReportError(err)
 		}
 	default:
 		fmt.Println("usage: go run main.go (filename)")
@@ -0,0 +1,267 @@
+// Package numeral converts between integers and their written forms: ascii
+// digits, full width digits and Chinese numerals.
+package numeral
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var digits = map[rune]int{
+	'零': 0,
+	'〇': 0,
+	'一': 1,
+	'二': 2,
+	'兩': 2,
+	'三': 3,
+	'四': 4,
+	'五': 5,
+	'六': 6,
+	'七': 7,
+	'八': 8,
+	'九': 9,
+}
+
+// units multiply the digit before them, 三百 -> 300
+var units = map[rune]int{
+	'十': 10,
+	'百': 100,
+	'千': 1000,
+}
+
+// colloquial tens, 廿一 -> 21
+var tens = map[rune]int{
+	'廿': 20,
+	'卅': 30,
+	'卌': 40,
+}
+
+// bigUnits multiply everything before them, 三十萬 -> 300000
+var bigUnits = map[rune]int{
+	'萬': 10000,
+	'億': 100000000,
+	'兆': 1000000000000,
+	'京': 10000000000000000,
+}
+
+var digitChars = []string{"零", "一", "二", "三", "四", "五", "六", "七", "八", "九"}
+var unitChars = []string{"", "十", "百", "千"}
+var bigUnitChars = []string{"", "萬", "億", "兆", "京"}
+
+// IsChinese reports whether char can be part of a Chinese numeral.
+func IsChinese(char rune) bool {
+	_, isDigit := digits[char]
+	_, isUnit := units[char]
+	_, isTen := tens[char]
+	_, isBigUnit := bigUnits[char]
+	return isDigit || isUnit || isTen || isBigUnit
+}
+
+// IsDigit reports whether char is an ascii or full width digit.
+func IsDigit(char rune) bool {
+	return (char >= '0' && char <= '9') || (char >= '０' && char <= '９')
+}
+
+// toASCII replaces full width digits and decimal points with ascii ones.
+func toASCII(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '０' && r <= '９' {
+			return r - '０' + '0'
+		}
+		if r == '．' {
+			return '.'
+		}
+		return r
+	}, s)
+}
+
+// Parse converts a number written with ascii digits, full width digits or
+// Chinese numerals to an int.
+func Parse(s string) (int, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty numeral")
+	}
+	if IsDigit([]rune(s)[0]) {
+		return strconv.Atoi(toASCII(s))
+	}
+	return parseChinese(s)
+}
+
+// ParseFloat converts a decimal written with ascii or full width digits.
+func ParseFloat(s string) (float64, error) {
+	return strconv.ParseFloat(toASCII(s), 64)
+}
+
+func parseChinese(s string) (int, error) {
+	runes := []rune(s)
+	positional := true
+	for _, r := range runes {
+		if _, ok := digits[r]; !ok {
+			positional = false
+		}
+	}
+	if positional {
+		// read digit by digit, 二〇二四 -> 2024
+		n := 0
+		for _, r := range runes {
+			if n > (math.MaxInt-digits[r])/10 {
+				return 0, fmt.Errorf("numeral %s out of range", s)
+			}
+			n = n*10 + digits[r]
+		}
+		return n, nil
+	}
+
+	total, section, number := 0, 0, 0
+	pendingDigit := false
+	// a digit right after a unit without 零 counts in the next lower unit,
+	// 三千五 -> 3500 and 兩萬三 -> 23000 but 三千零五 -> 3005
+	lastUnit, lastBigUnit := 0, 0
+	zero := false
+	lastDigit := func() int {
+		switch {
+		case !pendingDigit || zero:
+			return number
+		case lastUnit != 0:
+			return number * lastUnit / 10
+		case lastBigUnit != 0 && section == 0:
+			return number * lastBigUnit / 10
+		}
+		return number
+	}
+	for _, r := range runes {
+		if d, ok := digits[r]; ok {
+			if pendingDigit {
+				return 0, fmt.Errorf("invalid numeral %s", s)
+			}
+			number = d
+			pendingDigit = d != 0
+			zero = zero || d == 0
+		} else if u, ok := units[r]; ok {
+			if lastUnit != 0 && u >= lastUnit {
+				// units get smaller, 十十 is not a number
+				return 0, fmt.Errorf("invalid numeral %s", s)
+			}
+			if !pendingDigit {
+				// 十 -> 10
+				number = 1
+			}
+			section += number * u
+			number = 0
+			pendingDigit = false
+			lastUnit = u
+			zero = false
+		} else if t, ok := tens[r]; ok {
+			if pendingDigit || (lastUnit != 0 && lastUnit <= 10) {
+				return 0, fmt.Errorf("invalid numeral %s", s)
+			}
+			section += t
+			lastUnit = 10
+			zero = false
+		} else if u, ok := bigUnits[r]; ok {
+			section += lastDigit()
+			number = 0
+			pendingDigit = false
+			if section == 0 && total == 0 {
+				// 萬 -> 10000
+				section = 1
+			}
+			ok := true
+			if total < u {
+				// 一萬億 -> 1000000000000
+				total, ok = mulAdd(total+section, u, 0)
+			} else if u < lastBigUnit && section != 0 {
+				// 一億二千萬 -> 120000000
+				total, ok = mulAdd(section, u, total)
+			} else {
+				// 萬萬 and 一億三萬億 are not numbers
+				return 0, fmt.Errorf("invalid numeral %s", s)
+			}
+			if !ok {
+				return 0, fmt.Errorf("numeral %s out of range", s)
+			}
+			section = 0
+			lastUnit, lastBigUnit = 0, u
+			zero = false
+		} else {
+			return 0, fmt.Errorf("invalid numeral %s", s)
+		}
+	}
+	n, ok := mulAdd(1, total, section+lastDigit())
+	if !ok {
+		return 0, fmt.Errorf("numeral %s out of range", s)
+	}
+	return n, nil
+}
+
+// mulAdd returns a*b+c for non negative numbers, reporting whether it fits in
+// an int
+func mulAdd(a int, b int, c int) (int, bool) {
+	if b != 0 && a > (math.MaxInt-c)/b {
+		return 0, false
+	}
+	return a*b + c, true
+}
+
+// Format writes n in Chinese numerals, 10010 -> 一萬零一十
+func Format(n int) string {
+	if n == 0 {
+		return digitChars[0]
+	}
+	if n < 0 {
+		return "負" + formatUint(uint64(-(n+1))+1)
+	}
+	return formatUint(uint64(n))
+}
+
+func formatUint(n uint64) string {
+	sections := []int{}
+	for n > 0 {
+		sections = append(sections, int(n%10000))
+		n /= 10000
+	}
+	b := strings.Builder{}
+	zero := false
+	for i := len(sections) - 1; i >= 0; i-- {
+		sec := sections[i]
+		if sec == 0 {
+			zero = true
+			continue
+		}
+		if b.Len() > 0 && (zero || sec < 1000) {
+			b.WriteString(digitChars[0])
+		}
+		b.WriteString(formatSection(sec))
+		b.WriteString(bigUnitChars[i])
+		zero = false
+	}
+	s := b.String()
+	if strings.HasPrefix(s, digitChars[1]+unitChars[1]) {
+		// 一十二 -> 十二
+		s = strings.TrimPrefix(s, digitChars[1])
+	}
+	return s
+}
+
+// formatSection writes a number between 1 and 9999
+func formatSection(sec int) string {
+	b := strings.Builder{}
+	zero := false
+	pow := 1000
+	for i := 3; i >= 0; i-- {
+		d := sec / pow % 10
+		pow /= 10
+		if d == 0 {
+			zero = b.Len() > 0
+			continue
+		}
+		if zero {
+			b.WriteString(digitChars[0])
+			zero = false
+		}
+		b.WriteString(digitChars[d] + unitChars[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package numeral
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"0", 0},
+		{"42", 42},
+		{"４２", 42},
+		{"零", 0},
+		{"一", 1},
+		{"十", 10},
+		{"十一", 11},
+		{"二十", 20},
+		{"三十五", 35},
+		{"廿一", 21},
+		{"卅", 30},
+		{"卌五", 45},
+		{"兩百", 200},
+		{"一百零五", 105},
+		{"一千零一", 1001},
+		{"九千九百九十九", 9999},
+		{"萬", 10000},
+		{"十萬", 100000},
+		{"一萬零一十", 10010},
+		{"三億", 300000000},
+		{"一億零五", 100000005},
+		{"一億二千萬", 120000000},
+		{"一萬億", 1000000000000},
+		{"二〇二四", 2024},
+		{"一二三", 123},
+		{"三千五", 3500},
+		{"一百二", 120},
+		{"兩萬三", 23000},
+		{"一萬五千", 15000},
+		{"三千五萬", 35000000},
+		{"一億二", 120000000},
+		{"三千零五", 3005},
+		{"一百零二", 102},
+		{"兩萬零三", 20003},
+		{"一百一十", 110},
+		{"一百廿三", 123},
+		{"九百二十二京三千三百七十二兆零三百六十八億五千四百七十七萬五千八百零七", 9223372036854775807},
+		{"九二二三三七二零三六八五四七七五八零七", 9223372036854775807},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.input, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("%s: expected %d got %d", test.input, test.expected, got)
+		}
+	}
+
+	for _, input := range []string{"十二三", "五廿", "", "一x", "十十", "一百一千", "二十廿",
+		"萬萬", "京京", "一億萬", "一億三萬億", "一千京", "九百萬京", "一二三四五六七八九一二三四五六七八九一二"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("%s: expected error", input)
+		}
+	}
+}
+
+func TestParseFloat(t *testing.T) {
+	got, err := ParseFloat("３．５")
+	if err != nil || got != 3.5 {
+		t.Errorf("expected 3.5 got %g (%v)", got, err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		input    int
+		expected string
+	}{
+		{0, "零"},
+		{1, "一"},
+		{10, "十"},
+		{12, "十二"},
+		{20, "二十"},
+		{105, "一百零五"},
+		{110, "一百一十"},
+		{1001, "一千零一"},
+		{10010, "一萬零一十"},
+		{100000, "十萬"},
+		{120000000, "一億二千萬"},
+		{100000005, "一億零五"},
+		{123456789, "一億二千三百四十五萬六千七百八十九"},
+		{-35, "負三十五"},
+	}
+	for _, test := range tests {
+		got := Format(test.input)
+		if got != test.expected {
+			t.Errorf("%d: expected %s got %s", test.input, test.expected, got)
+		}
+		if test.input < 0 {
+			continue
+		}
+		back, err := Parse(got)
+		if err != nil || back != test.input {
+			t.Errorf("%s: expected round trip to %d got %d (%v)", got, test.input, back, err)
+		}
+	}
+}
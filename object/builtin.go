@@ -0,0 +1,126 @@
+package object
+
+import (
+	"fmt"
+	"sort"
+)
+
+// VARIADIC as MaxArgs lets a builtin take any number of arguments
+const VARIADIC = -1
+
+type BuiltInFunction func(rt *Runtime, args ...Object) Object
+
+// BuiltIn is a function written in Go, its arguments are counted before Fn
+// is called.
+type BuiltIn struct {
+	Name    string
+	MinArgs int
+	MaxArgs int
+	Doc     string
+	Fn      BuiltInFunction
+}
+
+// Arity describes how many arguments b takes, e.g. "1", "1 to 3", "2 or more"
+func (b *BuiltIn) Arity() string {
+	switch {
+	case b.MaxArgs == VARIADIC:
+		return fmt.Sprintf("%d or more", b.MinArgs)
+	case b.MinArgs == b.MaxArgs:
+		return fmt.Sprintf("%d", b.MinArgs)
+	default:
+		return fmt.Sprintf("%d to %d", b.MinArgs, b.MaxArgs)
+	}
+}
+
+// Accepts reports whether b can be called with n arguments
+func (b *BuiltIn) Accepts(n int) bool {
+	return n >= b.MinArgs && (b.MaxArgs == VARIADIC || n <= b.MaxArgs)
+}
+
+func (b *BuiltIn) Inspect() string {
+	return "<內置函數 builtin " + b.Name + ">"
+}
+func (b *BuiltIn) Type() string {
+	return BUILTIN_OBJ
+}
+
+// Registry holds the builtins a program can call by name.
+type Registry struct {
+	builtins map[string]*BuiltIn
+	aliases  map[string]string // other names for builtins
+}
+
+// NewRegistry creates a registry of builtins, naming each after its key.
+func NewRegistry(builtins map[string]*BuiltIn) *Registry {
+	r := &Registry{builtins: make(map[string]*BuiltIn), aliases: make(map[string]string)}
+	for name, b := range builtins {
+		b.Name = name
+		r.Register(b)
+	}
+	return r
+}
+
+// Register adds b, replacing any builtin or alias with the same name.
+func (r *Registry) Register(b *BuiltIn) {
+	delete(r.aliases, b.Name)
+	r.builtins[b.Name] = b
+}
+
+// Alias makes alias another name for the builtin name, reporting whether
+// name was found. The alias follows name when it is replaced or removed.
+func (r *Registry) Alias(alias string, name string) bool {
+	if _, ok := r.builtins[name]; !ok {
+		return false
+	}
+	delete(r.builtins, alias)
+	r.aliases[alias] = name
+	return true
+}
+
+// Remove removes a builtin or an alias.
+func (r *Registry) Remove(name string) {
+	delete(r.builtins, name)
+	delete(r.aliases, name)
+}
+
+// Get returns the builtin called name, a builtin found by an alias is named
+// after the alias.
+func (r *Registry) Get(name string) (*BuiltIn, bool) {
+	if b, ok := r.builtins[name]; ok {
+		return b, true
+	}
+	b, ok := r.builtins[r.aliases[name]]
+	if !ok {
+		return nil, false
+	}
+	aliased := *b
+	aliased.Name = name
+	return &aliased, true
+}
+
+// Names lists the registered builtins and aliases in order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.builtins)+len(r.aliases))
+	for name := range r.builtins {
+		names = append(names, name)
+	}
+	for alias, name := range r.aliases {
+		if _, ok := r.builtins[name]; ok {
+			names = append(names, alias)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Copy returns a registry that can be changed without affecting r.
+func (r *Registry) Copy() *Registry {
+	c := &Registry{builtins: make(map[string]*BuiltIn, len(r.builtins)), aliases: make(map[string]string, len(r.aliases))}
+	for name, b := range r.builtins {
+		c.builtins[name] = b
+	}
+	for alias, name := range r.aliases {
+		c.aliases[alias] = name
+	}
+	return c
+}
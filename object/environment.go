@@ -1,12 +1,59 @@
 package object
 
+import (
+	"io"
+	"os"
+)
+
 type Environment struct {
 	Parent   *Environment
 	Bindings map[string]Object
+	Runtime  *Runtime // shared by every environment of a program
+	Block    bool     // holds only the names bound by a match arm or catch, new variables go in Parent
+}
+
+// Runtime holds the state of a running program, including the modules it
+// imported.
+type Runtime struct {
+	Modules    map[string]*Module // imported modules by absolute path
+	Loading    []string           // modules being imported, innermost last
+	SearchPath []string           // directories to look for imports in
+	Stdout     io.Writer          // where 講 prints to
+	Stderr     io.Writer          // where errors are reported to
+	Stdin      io.Reader
+	Builtins   *Registry // nil uses the default builtins
 }
 
+func NewRuntime() *Runtime {
+	return &Runtime{Modules: make(map[string]*Module), Stdout: os.Stdout, Stderr: os.Stderr, Stdin: os.Stdin}
+}
+
+// NewEnvironment creates an environment inside parent, or the global
+// environment of a new program if parent is nil.
 func NewEnvironment(parent *Environment) *Environment {
-	return &Environment{Parent: parent, Bindings: make(map[string]Object)}
+	env := &Environment{Parent: parent, Bindings: make(map[string]Object)}
+	if parent != nil {
+		env.Runtime = parent.Runtime
+	} else {
+		env.Runtime = NewRuntime()
+	}
+	return env
+}
+
+// NewBlockEnvironment creates an environment for the names a block binds
+// itself, variables assigned in the block are set in the enclosing scope.
+func NewBlockEnvironment(parent *Environment) *Environment {
+	env := NewEnvironment(parent)
+	env.Block = true
+	return env
+}
+
+// Scope returns the environment new variables are set in, skipping blocks
+func (e *Environment) Scope() *Environment {
+	for e.Block {
+		e = e.Parent
+	}
+	return e
 }
 
 func (e *Environment) Set(varName string, value Object) {
@@ -20,3 +67,21 @@ func (e *Environment) Get(varName string) (Object, bool) {
 	}
 	return val, ok
 }
+
+// Assign rebinds varName in the closest environment that already defines it,
+// otherwise it is set in e's scope. Inside a function only the variables of
+// enclosing functions are rebound, never globals, so a function's variables
+// cannot change its caller's.
+func (e *Environment) Assign(varName string, value Object) {
+	scope := e.Scope()
+	for env := e; env != nil; env = env.Parent {
+		if env.Parent == nil && env != scope {
+			break
+		}
+		if _, ok := env.Bindings[varName]; ok {
+			env.Bindings[varName] = value
+			return
+		}
+	}
+	scope.Set(varName, value)
+}
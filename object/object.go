@@ -3,25 +3,39 @@ package object
 import (
 	"bytes"
 	"cantolang/ast"
+	"cantolang/token"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
 var (
 	NULL  = &Null{}
 	TRUE  = &Boolean{Value: true}
 	FALSE = &Boolean{Value: false}
-	ERROR = &Error{}
+
+	BREAK    = &Break{}
+	CONTINUE = &Continue{}
 
 	//types
 	INT_OBJ      = "INT_OBJ"
+	FLOAT_OBJ    = "FLOAT_OBJ"
 	STRING_OBJ   = "STRING_OBJ"
 	ARRAY_OBJ    = "ARRAY_OBJ"
+	HASH_OBJ     = "HASH_OBJ"
+	RANGE_OBJ    = "RANGE_OBJ"
 	NULL_OBJ     = "NULL_OBJ"
 	BOOL_OBJ     = "BOOL_OBJ"
 	ERROR_OBJ    = "ERROR_OBJ"
+	ERRVAL_OBJ   = "ERRVAL_OBJ"
 	FUNCTION_OBJ = "FUNCTION_OBJ"
 	RETURN_OBJ   = "RETURN_OBJ"
+	BREAK_OBJ    = "BREAK_OBJ"
+	CONTINUE_OBJ = "CONTINUE_OBJ"
 	BUILTIN_OBJ  = "BUILTIN_OBJ"
+	MODULE_OBJ   = "MODULE_OBJ"
 )
 
 type Object interface {
@@ -29,12 +43,14 @@ type Object interface {
 	Type() string
 }
 
-type BuiltInFunction func(args ...Object) Object
-
 type Integer struct {
 	Value int
 }
 
+type Float struct {
+	Value float64
+}
+
 type String struct {
 	Value string
 }
@@ -43,6 +59,35 @@ type Array struct {
 	Items []Object
 }
 
+// Hash keeps its pairs in insertion order
+type Hash struct {
+	Pairs map[HashKey]HashPair
+	Keys  []HashKey
+}
+
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+type HashKey struct {
+	Type  string
+	Value string
+}
+
+// Hashable objects can be used as hash keys
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+// Range is the integers from Start up to but not including End
+type Range struct {
+	Start int
+	End   int
+	Step  int
+}
+
 type Boolean struct {
 	Value bool
 }
@@ -53,27 +98,75 @@ type Null struct {
 type Error struct {
 	Message     string
 	Description string
+	Pos         token.Position // where the error was raised
+	Stack       []Frame        // calls unwound through, innermost first
+}
+
+// Module is an imported file, its top level bindings are its members
+type Module struct {
+	Name string
+	Path string
+	Env  *Environment
+}
+
+// ErrorValue is a caught error, it is a plain value and does not unwind
+type ErrorValue struct {
+	Error *Error
+}
+
+type Frame struct {
+	Function string
+	Pos      token.Position // where the function was called
 }
 
 type Function struct {
+	Name       string // empty for anonymous functions
 	Parameters []ast.Identifier
 	Body       *ast.BlockStatement
-}
-
-type BuiltIn struct {
-	Fn BuiltInFunction
+	Env        *Environment // environment the function was defined in
 }
 
 type ReturnValue struct {
 	Value Object
 }
 
+// Break and Continue unwind to the closest loop
+type Break struct {
+}
+
+type Continue struct {
+}
+
 func (i *Integer) Inspect() string {
 	return fmt.Sprintf("%d", i.Value)
 }
 func (i *Integer) Type() string {
 	return INT_OBJ
 }
+func (i *Integer) HashKey() HashKey {
+	return HashKey{Type: INT_OBJ, Value: strconv.Itoa(i.Value)}
+}
+
+func (f *Float) Inspect() string {
+	s := strconv.FormatFloat(f.Value, 'f', -1, 64)
+	if !strings.Contains(s, ".") && !math.IsInf(f.Value, 0) && !math.IsNaN(f.Value) {
+		s += ".0"
+	}
+	return s
+}
+func (f *Float) Type() string {
+	return FLOAT_OBJ
+}
+
+// Len is the number of characters (runes) in s, 有幾長（“你好”） is 2
+func (s *String) Len() int {
+	return utf8.RuneCountInString(s.Value)
+}
+
+// Runes splits s into characters, which strings are indexed by
+func (s *String) Runes() []rune {
+	return []rune(s.Value)
+}
 
 func (s *String) Inspect() string {
 	return s.Value
@@ -81,6 +174,9 @@ func (s *String) Inspect() string {
 func (s *String) Type() string {
 	return STRING_OBJ
 }
+func (s *String) HashKey() HashKey {
+	return HashKey{Type: STRING_OBJ, Value: s.Value}
+}
 
 func (a *Array) Inspect() string {
 	b := bytes.Buffer{}
@@ -98,12 +194,105 @@ func (a *Array) Type() string {
 	return ARRAY_OBJ
 }
 
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair)}
+}
+
+func (h *Hash) Get(key Hashable) (Object, bool) {
+	pair, ok := h.Pairs[key.HashKey()]
+	return pair.Value, ok
+}
+
+func (h *Hash) Set(key Hashable, value Object) {
+	hashKey := key.HashKey()
+	if _, ok := h.Pairs[hashKey]; !ok {
+		h.Keys = append(h.Keys, hashKey)
+	}
+	h.Pairs[hashKey] = HashPair{Key: key, Value: value}
+}
+
+func (h *Hash) Delete(key Hashable) {
+	hashKey := key.HashKey()
+	if _, ok := h.Pairs[hashKey]; !ok {
+		return
+	}
+	delete(h.Pairs, hashKey)
+	for i, k := range h.Keys {
+		if k == hashKey {
+			h.Keys = append(h.Keys[:i], h.Keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Copy returns a new hash with the same pairs
+func (h *Hash) Copy() *Hash {
+	hash := NewHash()
+	for _, k := range h.Keys {
+		hash.Pairs[k] = h.Pairs[k]
+	}
+	hash.Keys = append(hash.Keys, h.Keys...)
+	return hash
+}
+
+func (h *Hash) Inspect() string {
+	b := bytes.Buffer{}
+	b.WriteString("{")
+	for i, k := range h.Keys {
+		if i != 0 {
+			b.WriteString(", ")
+		}
+		pair := h.Pairs[k]
+		b.WriteString(pair.Key.Inspect() + ": " + pair.Value.Inspect())
+	}
+	b.WriteString("}")
+	return b.String()
+}
+func (h *Hash) Type() string {
+	return HASH_OBJ
+}
+
+// Len is the number of integers in the range
+// Len is the number of integers in r, at most math.MaxInt.
+func (r *Range) Len() int {
+	n := r.Len64()
+	if n > math.MaxInt {
+		return math.MaxInt
+	}
+	return int(n)
+}
+
+// Len64 is the number of integers in r, worked out in uint64 so ranges across
+// the whole int range do not overflow.
+func (r *Range) Len64() uint64 {
+	var diff, step uint64
+	switch {
+	case r.Step > 0 && r.Start < r.End:
+		diff, step = uint64(r.End)-uint64(r.Start), uint64(r.Step)
+	case r.Step < 0 && r.Start > r.End:
+		diff, step = uint64(r.Start)-uint64(r.End), -uint64(r.Step)
+	default:
+		return 0
+	}
+	return (diff-1)/step + 1
+}
+
+func (r *Range) Inspect() string {
+	return fmt.Sprintf("範圍(%d, %d, %d)", r.Start, r.End, r.Step)
+}
+func (r *Range) Type() string {
+	return RANGE_OBJ
+}
+
 func (b *Boolean) Inspect() string {
 	return fmt.Sprintf("%t", b.Value)
 }
 func (b *Boolean) Type() string {
 	return BOOL_OBJ
 }
+func (b *Boolean) HashKey() HashKey {
+	return HashKey{Type: BOOL_OBJ, Value: strconv.FormatBool(b.Value)}
+}
 
 func (n *Null) Inspect() string {
 	return "NULL"
@@ -113,12 +302,60 @@ func (n *Null) Type() string {
 }
 
 func (e *Error) Inspect() string {
+	if e.Pos.IsValid() {
+		return e.Pos.String() + ": " + e.Description + ": " + e.Message
+	}
 	return e.Description + ": " + e.Message
 }
+
+// Traceback lists the calls leading to the error, most recent call last,
+// followed by the error itself.
+func (e *Error) Traceback() string {
+	b := bytes.Buffer{}
+	if len(e.Stack) > 0 {
+		b.WriteString("追蹤（最近嘅呼叫喺最尾）traceback (most recent call last):\n")
+		for i := len(e.Stack) - 1; i >= 0; i-- {
+			b.WriteString("  " + e.Stack[i].Pos.String() + " 叫 call " + e.Stack[i].Function + "\n")
+		}
+	}
+	b.WriteString(e.Inspect())
+	return b.String()
+}
 func (e *Error) Type() string {
 	return ERROR_OBJ
 }
 
+func (m *Module) Get(name string) (Object, bool) {
+	val, ok := m.Env.Bindings[name]
+	return val, ok
+}
+func (m *Module) Inspect() string {
+	return "模組(" + m.Name + ")"
+}
+func (m *Module) Type() string {
+	return MODULE_OBJ
+}
+
+// Field looks up 訊息 (message), 描述 (description) or 位置 (position)
+func (ev *ErrorValue) Field(name string) (Object, bool) {
+	switch name {
+	case "訊息", "message":
+		return &String{Value: ev.Error.Message}, true
+	case "描述", "description":
+		return &String{Value: ev.Error.Description}, true
+	case "位置", "position":
+		return &String{Value: ev.Error.Pos.String()}, true
+	}
+	return nil, false
+}
+
+func (ev *ErrorValue) Inspect() string {
+	return "錯誤(" + ev.Error.Inspect() + ")"
+}
+func (ev *ErrorValue) Type() string {
+	return ERRVAL_OBJ
+}
+
 func (f *Function) Inspect() string {
 	b := bytes.Buffer{}
 	b.WriteString("(")
@@ -137,16 +374,23 @@ func (f *Function) Type() string {
 	return FUNCTION_OBJ
 }
 
-func (f *BuiltIn) Inspect() string {
-	return "builtin function"
-}
-func (f *BuiltIn) Type() string {
-	return BUILTIN_OBJ
-}
-
 func (r *ReturnValue) Inspect() string {
 	return "return " + r.Value.Inspect()
 }
 func (r *ReturnValue) Type() string {
 	return RETURN_OBJ
 }
+
+func (b *Break) Inspect() string {
+	return "break"
+}
+func (b *Break) Type() string {
+	return BREAK_OBJ
+}
+
+func (c *Continue) Inspect() string {
+	return "continue"
+}
+func (c *Continue) Type() string {
+	return CONTINUE_OBJ
+}
@@ -3,14 +3,18 @@ package parser
 import (
 	"cantolang/ast"
 	"cantolang/lexer"
+	"cantolang/numeral"
 	"cantolang/token"
 	"fmt"
-	"strconv"
+	"path/filepath"
+	"strings"
 )
 
 const (
 	_ int = iota
 	LOWEST
+	OR         // 或者
+	AND        // 同埋
 	EQUALS     // ==
 	LESSGRATER // > or <
 	SUM        // +
@@ -21,15 +25,21 @@ const (
 )
 
 var precedences = map[string]int{
-	token.EQUAL_TO:     EQUALS,
-	token.LESS_THAN:    LESSGRATER,
-	token.GREATER_THAN: LESSGRATER,
-	token.ADD:          SUM,
-	token.MINUS:        SUM,
-	token.MULTIPLY:     PRODUCT,
-	token.DIVIDE:       PRODUCT,
-	token.OPEN_PAREN:   CALL,
-	token.OPEN_BRACKET: INDEX,
+	token.OR:            OR,
+	token.AND:           AND,
+	token.EQUAL_TO:      EQUALS,
+	token.NOT_EQUAL_TO:  EQUALS,
+	token.LESS_THAN:     LESSGRATER,
+	token.GREATER_THAN:  LESSGRATER,
+	token.LESS_EQUAL:    LESSGRATER,
+	token.GREATER_EQUAL: LESSGRATER,
+	token.ADD:           SUM,
+	token.MINUS:         SUM,
+	token.MULTIPLY:      PRODUCT,
+	token.DIVIDE:        PRODUCT,
+	token.OPEN_PAREN:    CALL,
+	token.OPEN_BRACKET:  INDEX,
+	token.DOT:           INDEX,
 }
 
 type Parser struct {
@@ -39,6 +49,7 @@ type Parser struct {
 	Errors       []string
 	prefixes     []string
 	infixes      []string
+	loopDepth    int // number of loops around the current statement
 }
 
 func New(l *lexer.Lexer) *Parser {
@@ -46,7 +57,11 @@ func New(l *lexer.Lexer) *Parser {
 	p.advance()
 	p.advance()
 	p.prefixes = []string{token.MINUS, token.NOT}
-	p.infixes = []string{token.ADD, token.MINUS, token.MULTIPLY, token.DIVIDE, token.EQUAL_TO, token.GREATER_THAN, token.LESS_THAN}
+	p.infixes = []string{
+		token.ADD, token.MINUS, token.MULTIPLY, token.DIVIDE,
+		token.EQUAL_TO, token.NOT_EQUAL_TO, token.GREATER_THAN, token.LESS_THAN, token.GREATER_EQUAL, token.LESS_EQUAL,
+		token.AND, token.OR,
+	}
 
 	return p
 }
@@ -56,10 +71,15 @@ func (p *Parser) advance() {
 	p.peekToken = p.lexer.ReadToken()
 }
 
+// errorf records a parser error prefixed with the file:line:col of pos.
+func (p *Parser) errorf(pos token.Position, format string, a ...interface{}) {
+	p.Errors = append(p.Errors, pos.String()+": "+fmt.Sprintf(format, a...))
+}
+
 func (p *Parser) expectPeek(expectedTokenType string) bool {
 	p.advance()
 	if p.currentToken.TokenType != expectedTokenType {
-		p.Errors = append(p.Errors, fmt.Sprintf("expected %s got %s (%s)", expectedTokenType, p.currentToken.TokenType, p.currentToken.TokenLiteral))
+		p.errorf(p.currentToken.Pos, "expected %s got %s (%s)", expectedTokenType, p.currentToken.TokenType, p.currentToken.TokenLiteral)
 		return false
 	}
 	return true
@@ -76,9 +96,22 @@ func (p *Parser) ParseStatement() ast.Statement {
 	case token.ASSIGN:
 		s = p.parseAssignStatement()
 	case token.FUNCTION:
-		s = p.parseFunctionDefStatement()
+		if p.peekToken.TokenType == token.IDENTIFIER {
+			s = p.parseFunctionDefStatement()
+		} else {
+			// anonymous function
+			s = p.parseExpressionStatement()
+		}
 	case token.WHILE:
 		s = p.parseWhileLoop()
+	case token.FOREACH:
+		s = p.parseForEachLoop()
+	case token.IMPORT:
+		s = p.parseImportStatement()
+	case token.BREAK:
+		s = p.parseLoopControl(&ast.BreakStatement{Token: p.currentToken})
+	case token.CONTINUE:
+		s = p.parseLoopControl(&ast.ContinueStatement{Token: p.currentToken})
 	case token.IDENTIFIER:
 		// check for in/decrement
 		switch p.peekToken.TokenType {
@@ -87,6 +120,7 @@ func (p *Parser) ParseStatement() ast.Statement {
 				Token:       p.currentToken,
 				Identifier:  p.currentToken.TokenLiteral,
 				IsIncrement: true,
+				EndToken:    p.peekToken,
 			}
 			p.advance()
 			p.advance()
@@ -95,6 +129,7 @@ func (p *Parser) ParseStatement() ast.Statement {
 				Token:       p.currentToken,
 				Identifier:  p.currentToken.TokenLiteral,
 				IsIncrement: false,
+				EndToken:    p.peekToken,
 			}
 			p.advance()
 			p.advance()
@@ -108,6 +143,17 @@ func (p *Parser) ParseStatement() ast.Statement {
 	return s
 }
 
+// parseLoopControl parses 停 or 跳過, which are only allowed inside a loop
+func (p *Parser) parseLoopControl(statement ast.Statement) ast.Statement {
+	if p.loopDepth == 0 {
+		p.errorf(p.currentToken.Pos, "%s(%s) outside loop", p.currentToken.TokenLiteral, p.currentToken.TokenType)
+	}
+	if p.peekToken.TokenType == token.EOL {
+		p.advance()
+	}
+	return statement
+}
+
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	statement := &ast.ReturnStatement{Token: p.currentToken}
 	p.advance()
@@ -122,15 +168,30 @@ func (p *Parser) parseAssignStatement() *ast.AssignStatement {
 	statement := &ast.AssignStatement{Token: p.currentToken}
 	p.advance()
 	statement.Expression = p.parseExpression(LOWEST)
-	p.expectPeek(token.TO)
-	p.expectPeek(token.IDENTIFIER)
-	statement.Identifier = p.currentToken.TokenLiteral
+	if !p.expectPeek(token.TO) {
+		return nil
+	}
+	p.advance()
+	statement.Target = p.parseExpression(LOWEST)
+	if !isAssignable(statement.Target) {
+		p.errorf(p.currentToken.Pos, "cannot assign to %T", statement.Target)
+		return nil
+	}
 	if p.peekToken.TokenType == token.EOL {
 		p.advance()
 	}
 	return statement
 }
 
+// isAssignable reports whether target is a variable or an element, e.g. a【1】【2】
+func isAssignable(target ast.Expression) bool {
+	switch target.(type) {
+	case *ast.Identifier, *ast.IndexExpression:
+		return true
+	}
+	return false
+}
+
 func (p *Parser) parseWhileLoop() *ast.WhileLoop {
 	loop := &ast.WhileLoop{Token: p.currentToken}
 	if !p.expectPeek(token.OPEN_PAREN) {
@@ -153,25 +214,27 @@ func (p *Parser) parseWhileLoop() *ast.WhileLoop {
 	if !p.expectPeek(token.OPEN_BRACE) {
 		return nil
 	}
-	p.advance()
+	p.loopDepth++
 	loop.Body = p.parseBlockStatement()
+	p.loopDepth--
 	if p.peekToken.TokenType == token.EOL {
 		p.advance()
 	}
 	return loop
 }
 
-func (p *Parser) parseFunctionDefStatement() *ast.FunctionDefStatment {
-	statement := &ast.FunctionDefStatment{Token: p.currentToken}
+func (p *Parser) parseForEachLoop() *ast.ForEachLoop {
+	loop := &ast.ForEachLoop{Token: p.currentToken}
 	if !p.expectPeek(token.IDENTIFIER) {
 		return nil
 	}
-	statement.Identifier = p.currentToken.TokenLiteral
-	if !p.expectPeek(token.OPEN_PAREN) {
+	loop.Variable = &ast.Identifier{Token: p.currentToken}
+	if !p.expectPeek(token.HAI) {
 		return nil
 	}
-	statement.Parameters = p.parseParams()
-	if !p.expectPeek(token.GEWA) {
+	p.advance()
+	loop.Iterable = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.DOU) {
 		return nil
 	}
 	if !p.expectPeek(token.COMMA) {
@@ -183,8 +246,52 @@ func (p *Parser) parseFunctionDefStatement() *ast.FunctionDefStatment {
 	if !p.expectPeek(token.OPEN_BRACE) {
 		return nil
 	}
-	p.advance()
-	statement.Body = p.parseBlockStatement()
+	p.loopDepth++
+	loop.Body = p.parseBlockStatement()
+	p.loopDepth--
+	if p.peekToken.TokenType == token.EOL {
+		p.advance()
+	}
+	return loop
+}
+
+// parseImportStatement parses 引入 "path" 做 name, without 做 the module is
+// named after the file, 引入 "lib/數學.txt" -> 數學
+func (p *Parser) parseImportStatement() *ast.ImportStatement {
+	statement := &ast.ImportStatement{Token: p.currentToken}
+	if !p.expectPeek(token.STRING) {
+		return nil
+	}
+	statement.Path = &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.TokenLiteral}
+	if p.peekToken.TokenType == token.AS {
+		p.advance()
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
+		statement.Name = &ast.Identifier{Token: p.currentToken}
+	} else {
+		base := filepath.Base(statement.Path.Value)
+		nameToken := p.currentToken
+		nameToken.TokenType = token.IDENTIFIER
+		nameToken.TokenLiteral = strings.TrimSuffix(base, filepath.Ext(base))
+		statement.Name = &ast.Identifier{Token: nameToken}
+	}
+	if p.peekToken.TokenType == token.EOL {
+		p.advance()
+	}
+	return statement
+}
+
+func (p *Parser) parseFunctionDefStatement() *ast.FunctionDefStatment {
+	statement := &ast.FunctionDefStatment{Token: p.currentToken}
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+	statement.Identifier = p.currentToken.TokenLiteral
+	statement.Parameters, statement.Body = p.parseFunction()
+	if statement.Body == nil {
+		return nil
+	}
 	if p.peekToken.TokenType == token.EOL {
 		p.advance()
 	}
@@ -192,6 +299,41 @@ func (p *Parser) parseFunctionDefStatement() *ast.FunctionDefStatment {
 
 }
 
+func (p *Parser) parseFunctionLiteral() ast.Expression {
+	literal := &ast.FunctionLiteral{Token: p.currentToken}
+	literal.Parameters, literal.Body = p.parseFunction()
+	if literal.Body == nil {
+		return nil
+	}
+	return literal
+}
+
+// parseFunction parses the （params） 嘅話，就「body」 part of a function
+func (p *Parser) parseFunction() ([]ast.Identifier, *ast.BlockStatement) {
+	if !p.expectPeek(token.OPEN_PAREN) {
+		return nil, nil
+	}
+	params := p.parseParams()
+	if !p.expectPeek(token.GEWA) {
+		return nil, nil
+	}
+	if !p.expectPeek(token.COMMA) {
+		return nil, nil
+	}
+	if !p.expectPeek(token.THEN) {
+		return nil, nil
+	}
+	if !p.expectPeek(token.OPEN_BRACE) {
+		return nil, nil
+	}
+	// loops outside the function cannot be broken from inside
+	loopDepth := p.loopDepth
+	p.loopDepth = 0
+	body := p.parseBlockStatement()
+	p.loopDepth = loopDepth
+	return params, body
+}
+
 func (p *Parser) parseParams() []ast.Identifier {
 	params := []ast.Identifier{}
 	for p.peekToken.TokenType == token.IDENTIFIER {
@@ -238,6 +380,25 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	return statement
 }
 
+// parseTemplate parses each {expression} of a string with its own parser
+func (p *Parser) parseTemplate() ast.Expression {
+	template := &ast.TemplateLiteral{Token: p.currentToken}
+	for _, part := range p.currentToken.Parts {
+		if !part.IsExpr {
+			template.Parts = append(template.Parts, &ast.StringLiteral{Token: p.currentToken, Value: part.Value})
+			continue
+		}
+		sub := New(lexer.NewAt(part.Pos, part.Value))
+		expression := sub.parseExpression(LOWEST)
+		if len(sub.Errors) == 0 && sub.peekToken.TokenType != token.EOF {
+			sub.errorf(sub.peekToken.Pos, "expected } got %s (%s)", sub.peekToken.TokenType, sub.peekToken.TokenLiteral)
+		}
+		p.Errors = append(p.Errors, sub.Errors...)
+		template.Parts = append(template.Parts, expression)
+	}
+	return template
+}
+
 func (p *Parser) parseExpression(precedence int) ast.Expression {
 	// check for prefix
 	var left ast.Expression
@@ -252,23 +413,41 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 			left = p.parseGroupedExpression()
 		case token.OPEN_BRACKET:
 			left = p.parseArray()
+		case token.OPEN_BRACE:
+			left = p.parseHash()
 		case token.IF:
 			left = p.parseIfExpression()
+		case token.MATCH:
+			left = p.parseMatchExpression()
+		case token.TRY:
+			left = p.parseTryExpression()
+		case token.FUNCTION:
+			left = p.parseFunctionLiteral()
 		case token.TRUE:
 			left = &ast.Boolean{Token: p.currentToken, Value: true}
 		case token.FALSE:
 			left = &ast.Boolean{Token: p.currentToken, Value: false}
 		case token.NUMBER:
-			val, err := strconv.Atoi(p.currentToken.TokenLiteral)
+			val, err := numeral.Parse(p.currentToken.TokenLiteral)
 			if err != nil {
-				p.Errors = append(p.Errors, fmt.Sprintf("cannot convert %s(%s) to number", p.currentToken.TokenLiteral, p.currentToken.TokenType))
+				p.errorf(p.currentToken.Pos, "cannot convert %s(%s) to number", p.currentToken.TokenLiteral, p.currentToken.TokenType)
 			}
 			left = &ast.IntegerLiteral{Token: p.currentToken, Value: val}
+		case token.FLOAT:
+			val, err := numeral.ParseFloat(p.currentToken.TokenLiteral)
+			if err != nil {
+				p.errorf(p.currentToken.Pos, "cannot convert %s(%s) to number", p.currentToken.TokenLiteral, p.currentToken.TokenType)
+			}
+			left = &ast.FloatLiteral{Token: p.currentToken, Value: val}
 		case token.STRING:
 			left = &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.TokenLiteral}
+		case token.TEMPLATE:
+			left = p.parseTemplate()
+		case token.ERROR:
+			p.errorf(p.currentToken.Pos, "%s", p.currentToken.TokenLiteral)
 
 		default:
-			p.Errors = append(p.Errors, fmt.Sprintf("invalid token %s(%s)", p.currentToken.TokenLiteral, p.currentToken.TokenType))
+			p.errorf(p.currentToken.Pos, "invalid token %s(%s)", p.currentToken.TokenLiteral, p.currentToken.TokenType)
 		}
 	}
 
@@ -284,8 +463,11 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 		} else if p.currentToken.TokenType == token.OPEN_BRACKET {
 			left = p.parseIndexExpression(left)
 			continue
+		} else if p.currentToken.TokenType == token.DOT {
+			left = p.parseMemberExpression(left)
+			continue
 		}
-		p.Errors = append(p.Errors, fmt.Sprintf("infix token expected, got %s", p.currentToken.TokenType))
+		p.errorf(p.currentToken.Pos, "infix token expected, got %s", p.currentToken.TokenType)
 	}
 	return left
 }
@@ -294,7 +476,7 @@ func (p *Parser) parseGroupedExpression() ast.Expression {
 	ex := p.parseExpression(LOWEST)
 	p.advance()
 	if p.currentToken.TokenType != token.CLOSE_PAREN {
-		p.Errors = append(p.Errors, fmt.Sprintf("expected ) got %s", p.currentToken.TokenLiteral))
+		p.errorf(p.currentToken.Pos, "expected ) got %s", p.currentToken.TokenLiteral)
 		return nil
 	}
 	return ex
@@ -311,75 +493,236 @@ func (p *Parser) parseArray() ast.Expression {
 		}
 	}
 	if p.currentToken.TokenType == token.CLOSE_BRACKET {
+		arr.EndToken = p.currentToken
 		return arr
 	}
 	return nil
 }
 
+func (p *Parser) parseHash() ast.Expression {
+	hash := &ast.HashLiteral{Token: p.currentToken}
+	for p.peekToken.TokenType != token.CLOSE_BRACE {
+		p.advance()
+		key := p.parseExpression(LOWEST)
+		if !p.expectPeek(token.COLON) {
+			return nil
+		}
+		p.advance()
+		value := p.parseExpression(LOWEST)
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
+		if p.peekToken.TokenType != token.CLOSE_BRACE && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	p.advance()
+	hash.EndToken = p.currentToken
+	return hash
+}
+
 func (p *Parser) parseIfExpression() ast.Expression {
 	ex := &ast.IfExpression{Token: p.currentToken}
-	if !p.expectPeek(token.OPEN_PAREN) {
+	ex.Condition, ex.Consequence = p.parseConditionalBlock()
+	if ex.Consequence == nil {
 		return nil
 	}
+
+	for {
+		if p.peekToken.TokenType == token.ELSE_IF {
+			p.advance()
+		} else if p.peekToken.TokenType == token.ELSE {
+			p.advance()
+			if p.peekToken.TokenType != token.IF {
+				if !p.expectPeek(token.OPEN_BRACE) {
+					return nil
+				}
+				ex.Alternative = p.parseBlockStatement()
+				break
+			}
+			// 唔係就 如果
+			p.advance()
+		} else {
+			break
+		}
+		elseIf := &ast.ElseIf{Token: p.currentToken}
+		elseIf.Condition, elseIf.Consequence = p.parseConditionalBlock()
+		if elseIf.Consequence == nil {
+			return nil
+		}
+		ex.ElseIfs = append(ex.ElseIfs, elseIf)
+	}
+	return ex
+}
+
+// parseConditionalBlock parses （condition） 嘅話，就「…」 after an if or else if
+func (p *Parser) parseConditionalBlock() (ast.Expression, *ast.BlockStatement) {
+	if !p.expectPeek(token.OPEN_PAREN) {
+		return nil, nil
+	}
 	p.advance()
-	ex.Condition = p.parseExpression(LOWEST)
+	condition := p.parseExpression(LOWEST)
 
 	if !p.expectPeek(token.CLOSE_PAREN) {
-		return nil
+		return nil, nil
 	}
 	if !p.expectPeek(token.GEWA) {
-		return nil
+		return nil, nil
 	}
 	if !p.expectPeek(token.COMMA) {
-		return nil
+		return nil, nil
 	}
 	if !p.expectPeek(token.THEN) {
+		return nil, nil
+	}
+	if !p.expectPeek(token.OPEN_BRACE) {
+		return nil, nil
+	}
+	return condition, p.parseBlockStatement()
+}
+
+func (p *Parser) parseTryExpression() ast.Expression {
+	ex := &ast.TryExpression{Token: p.currentToken}
+	if !p.expectPeek(token.OPEN_BRACE) {
 		return nil
 	}
+	ex.Body = p.parseBlockStatement()
+	if !p.expectPeek(token.CATCH) {
+		return nil
+	}
+	if p.peekToken.TokenType == token.OPEN_PAREN {
+		p.advance()
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
+		ex.ErrorName = &ast.Identifier{Token: p.currentToken}
+		if !p.expectPeek(token.CLOSE_PAREN) {
+			return nil
+		}
+	}
 	if !p.expectPeek(token.OPEN_BRACE) {
 		return nil
 	}
-	p.advance()
-	ex.Consequence = p.parseBlockStatement()
+	ex.Catch = p.parseBlockStatement()
+	return ex
+}
 
-	if p.peekToken.TokenType == token.ELSE {
-		p.advance()
+func (p *Parser) parseMatchExpression() ast.Expression {
+	ex := &ast.MatchExpression{Token: p.currentToken}
+	if !p.expectPeek(token.OPEN_PAREN) {
+		return nil
+	}
+	p.advance()
+	ex.Value = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.CLOSE_PAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.OPEN_BRACE) {
+		return nil
+	}
+	p.advance()
+	for p.currentToken.TokenType != token.CLOSE_BRACE {
+		switch p.currentToken.TokenType {
+		case token.EOF:
+			p.errorf(p.currentToken.Pos, "expected %s got %s", token.CLOSE_BRACE, token.EOF)
+			return nil
+		case token.EOL:
+			p.advance()
+			continue
+		}
+		arm := &ast.MatchArm{}
+		if p.currentToken.TokenType != token.DEFAULT {
+			errCount := len(p.Errors)
+			arm.Pattern = p.parseExpression(LOWEST)
+			if arm.Pattern == nil || len(p.Errors) > errCount {
+				// parseExpression has reported the error
+				return nil
+			}
+			if !isPattern(arm.Pattern) {
+				p.errorf(arm.Pattern.Pos(), "invalid pattern %s", arm.Pattern)
+				return nil
+			}
+		}
+		if p.peekToken.TokenType == token.COMMA {
+			p.advance()
+		}
+		if !p.expectPeek(token.THEN) {
+			return nil
+		}
 		if !p.expectPeek(token.OPEN_BRACE) {
 			return nil
 		}
+		arm.Body = p.parseBlockStatement()
+		ex.Arms = append(ex.Arms, arm)
 		p.advance()
-		ex.Alternative = p.parseBlockStatement()
 	}
+	ex.EndToken = p.currentToken
 	return ex
 }
 
+// isPattern reports whether expression can be matched against, patterns are
+// literals, identifiers which bind the value and arrays of patterns
+func isPattern(expression ast.Expression) bool {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.StringLiteral, *ast.Boolean, *ast.Identifier:
+		return true
+	case *ast.PrefixExpression:
+		if expression.PrefixToken.TokenType != token.MINUS {
+			return false
+		}
+		switch expression.Right.(type) {
+		case *ast.IntegerLiteral, *ast.FloatLiteral:
+			return true
+		}
+	case *ast.ArrayLiteral:
+		for _, item := range expression.Items {
+			if !isPattern(item) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	exp := &ast.IndexExpression{Left: left, Token: p.currentToken}
 	p.advance()
 	index := p.parseExpression(LOWEST)
 	p.expectPeek(token.CLOSE_BRACKET)
+	exp.EndToken = p.currentToken
 	exp.Index = index
 	return exp
 }
 
-func (p *Parser) parseFunctionCall(left ast.Expression) ast.Expression {
-	id, ok := left.(*ast.Identifier)
-	if !ok {
-		p.Errors = append(p.Errors, fmt.Sprintf("expected identifier got %T", left))
+func (p *Parser) parseMemberExpression(left ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{Token: p.currentToken, Object: left}
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
 	}
-	fce := &ast.FunctionCallExpression{Identifier: id}
+	exp.Member = &ast.Identifier{Token: p.currentToken}
+	return exp
+}
+
+func (p *Parser) parseFunctionCall(left ast.Expression) ast.Expression {
+	fce := &ast.FunctionCallExpression{Function: left}
 	fce.Parameters = p.parseCallParams()
+	fce.EndToken = p.currentToken
 	return fce
 }
 
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
-	bs := &ast.BlockStatement{}
+	bs := &ast.BlockStatement{Token: p.currentToken}
+	p.advance()
 	for p.currentToken.TokenType != token.CLOSE_BRACE {
+		if p.currentToken.TokenType == token.EOF {
+			p.errorf(p.currentToken.Pos, "expected %s got %s", token.CLOSE_BRACE, token.EOF)
+			break
+		}
 		s := p.ParseStatement()
 		if s != nil {
 			bs.Statements = append(bs.Statements, s)
 		}
 	}
+	bs.EndToken = p.currentToken
 	return bs
 }
 
@@ -412,7 +755,7 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	expression := &ast.InfixExpression{Left: left, Infix: p.currentToken}
 	precedence, ok := precedences[expression.Infix.TokenType]
 	if !ok {
-		p.Errors = append(p.Errors, fmt.Sprintf("Infix not found: %s", expression.Infix.TokenType))
+		p.errorf(expression.Infix.Pos, "Infix not found: %s", expression.Infix.TokenType)
 		p.advance()
 		return nil
 	}
@@ -4,6 +4,7 @@ import (
 	"cantolang/ast"
 	"cantolang/lexer"
 	"cantolang/token"
+	"fmt"
 	"testing"
 )
 
@@ -41,6 +42,37 @@ func TestIntegerStatements(t *testing.T) {
 	}
 }
 
+func TestFloatStatements(t *testing.T) {
+	input := `
+	2.5。
+	0．25。
+	`
+	expected := []float64{2.5, 0.25}
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(p, t)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("len(program) expected 2 got %d", len(program.Statements))
+	}
+
+	for i, st := range program.Statements {
+		exprStatement, ok := st.(*ast.ExpressionStatement)
+		if !ok {
+			t.Errorf("[%d] expected type ast.ExpressionStatement got %T", i, st)
+		}
+		floatLit, ok := exprStatement.Expression.(*ast.FloatLiteral)
+		if !ok {
+			t.Fatalf("[%d] expected type ast.FloatLiteral got %T", i, exprStatement.Expression)
+		}
+		if floatLit.Value != expected[i] {
+			t.Errorf("[%d] expected value '%g' got %g", i, expected[i], floatLit.Value)
+		}
+	}
+}
+
 func TestBoolStatements(t *testing.T) {
 	input := `
 	啱。
@@ -159,6 +191,43 @@ func TestInfixStatements(t *testing.T) {
 	}
 }
 
+func TestLogicalStatements(t *testing.T) {
+	input := `
+	a 同埋 b。
+	a 或者 b 同埋 c。
+	a 同埋 b 或者 c。
+	1 細過 2 同埋 2 細過 3。
+	a 係 b 或者 唔係 c。
+	（a 或者 b） 同埋 c。
+	a <= b 同埋 b != c。
+	a 唔細過 b + 1 == 啱。
+	`
+	expected := []string{
+		"(a 同埋 b)",
+		"(a 或者 (b 同埋 c))",
+		"((a 同埋 b) 或者 c)",
+		"((1 細過 2) 同埋 (2 細過 3))",
+		"((a 係 b) 或者 唔係c)",
+		"((a 或者 b) 同埋 c)",
+		"((a <= b) 同埋 (b != c))",
+		"((a 唔細過 (b + 1)) == 啱)",
+	}
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(p, t)
+	if len(program.Statements) != len(expected) {
+		t.Fatalf("len(program) expected %d got %d", len(expected), len(program.Statements))
+	}
+
+	for i, st := range program.Statements {
+		if st.String() != expected[i] {
+			t.Errorf("[%d] expected string %s got %s", i, expected[i], st.String())
+		}
+	}
+}
+
 func TestSingleIdentStatment(t *testing.T) {
 	input := `x`
 	l := lexer.New(input)
@@ -366,13 +435,40 @@ func TestFunctionCall(t *testing.T) {
 		if !ok {
 			t.Errorf("expected FunctionCallExpression got %T", es.Expression)
 		}
-		if fce.Identifier.String() != test.expected {
-			t.Errorf("expected add got %s", fce.Identifier.String())
+		if fce.Function.String() != test.expected {
+			t.Errorf("expected add got %s", fce.Function.String())
 		}
 	}
 
 }
 
+func TestFunctionLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`塞 聽到（x） 嘅話，就「 俾我 x。 」 入 f`, "塞聽到(x) {俾我x}-> f"},
+		{`【聽到（） 嘅話，就「 1 」】【0】（）`, "[聽到() {1}][0]()"},
+		{`聽到（x，y） 嘅話，就「 x + y 」（1，2）`, "聽到(x,y) {(x + y)}(1, 2)"},
+		{`（聽到（x） 嘅話，就「 x 」）（1）`, "聽到(x) {x}(1)"},
+		{`apply（聽到（x） 嘅話，就「 x 」， 2）`, "apply(聽到(x) {x}, 2)"},
+		{`f（1）（2）`, "f(1)(2)"},
+	}
+	for _, test := range tests {
+		l := lexer.New(test.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(p, t)
+		if len(program.Statements) != 1 {
+			t.Errorf("len(program) expected 1 got %d", len(program.Statements))
+			continue
+		}
+		if program.Statements[0].String() != test.expected {
+			t.Errorf("expected %s got %s", test.expected, program.Statements[0].String())
+		}
+	}
+}
+
 func TestAssignStatement(t *testing.T) {
 	input := "塞 (2 + 3) 入 i"
 	l := lexer.New(input)
@@ -395,6 +491,47 @@ func TestAssignStatement(t *testing.T) {
 	}
 }
 
+func TestAssignTargets(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		target   string
+	}{
+		{"塞 1 入 i", "i", "*ast.Identifier"},
+		{"塞 5 入 arr【2】", "arr[2]", "*ast.IndexExpression"},
+		{"塞 5 入 m[0][i + 1]。", "m[0][(i + 1)]", "*ast.IndexExpression"},
+		{`塞 “阿明” 入 h【“名”】`, `h["名"]`, "*ast.IndexExpression"},
+	}
+	for _, test := range tests {
+		l := lexer.New(test.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(p, t)
+		if len(program.Statements) != 1 {
+			t.Fatalf("len(program) expected 1 got %d", len(program.Statements))
+		}
+		as, ok := program.Statements[0].(*ast.AssignStatement)
+		if !ok {
+			t.Fatalf("expected AssignStatement got %T", program.Statements[0])
+		}
+		if as.Target.String() != test.expected {
+			t.Errorf("expected target %s got %s", test.expected, as.Target.String())
+		}
+		if fmt.Sprintf("%T", as.Target) != test.target {
+			t.Errorf("expected target type %s got %T", test.target, as.Target)
+		}
+	}
+
+	for _, input := range []string{"塞 1 入 2", "塞 1 入 f（）", "塞 1 入 a + b"} {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+		if len(p.Errors) == 0 {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
 func TestStringStatements(t *testing.T) {
 	input := `
 	"joe"。
@@ -423,6 +560,35 @@ func TestStringStatements(t *testing.T) {
 	}
 }
 
+func TestTemplateLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`“你好，{名}！”`, `"你好，{名}！"`},
+		{`"{a + b * 2}"`, `"{(a + (b * 2))}"`},
+		{`"x{有幾長（y）}y{{"k": "v"}["k"]}"`, `"x{有幾長(y)}y{{"k": "v"}["k"]}"`},
+		{`"{"a{1}b"}"`, `"{"a{1}b"}"`},
+	}
+	for _, test := range tests {
+		p := New(lexer.New(test.input))
+		program := p.ParseProgram()
+		checkParserErrors(p, t)
+		if len(program.Statements) != 1 {
+			t.Fatalf("len(program) expected 1 got %d", len(program.Statements))
+		}
+		exprStatement := program.Statements[0].(*ast.ExpressionStatement)
+		template, ok := exprStatement.Expression.(*ast.TemplateLiteral)
+		if !ok {
+			t.Errorf("expected ast.TemplateLiteral got %T", exprStatement.Expression)
+			continue
+		}
+		if template.String() != test.expected {
+			t.Errorf("expected %s got %s", test.expected, template.String())
+		}
+	}
+}
+
 func TestArrayStatements(t *testing.T) {
 	input := `
 	[1,2,3]。
@@ -452,6 +618,38 @@ func TestArrayStatements(t *testing.T) {
 	}
 }
 
+func TestHashStatements(t *testing.T) {
+	input := `
+	「“名”： “阿明”，“歲”： 30」。
+	{1: 2 + 3, 啱: [x]}。
+	「」。
+	塞 「“a”：1」 入 h。
+	`
+	expected := []string{`{"名": "阿明", "歲": 30}`, `{1: (2 + 3), 啱: [x]}`, "{}", `塞{"a": 1}-> h`}
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(p, t)
+
+	if len(program.Statements) != len(expected) {
+		t.Fatalf("len(program) expected %d got %d", len(expected), len(program.Statements))
+	}
+
+	for i, st := range program.Statements {
+		if st.String() != expected[i] {
+			t.Errorf("[%d] expected value '%s' got %s", i, expected[i], st.String())
+		}
+	}
+
+	l = lexer.New("「“a” 1」")
+	p = New(l)
+	p.ParseProgram()
+	if len(p.Errors) == 0 {
+		t.Errorf("expected error for missing colon")
+	}
+}
+
 func TestIndexStatements(t *testing.T) {
 	input := `
 	[1,2,3][69]。
@@ -496,7 +694,7 @@ func TestIndexStatements(t *testing.T) {
 		t.Errorf("expected type ast.IntegerLiteral got %T", exp.Index)
 	}
 	if ie.Infix.TokenType != token.ADD {
-		t.Errorf("exp + got %s", ie.Infix)
+		t.Errorf("exp + got %s", ie.Infix.TokenType)
 	}
 
 }
@@ -577,3 +775,375 @@ func TestIncrement(t *testing.T) {
 		t.Errorf("stmt is not decrement")
 	}
 }
+
+func TestErrorPosition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"當 （i 細過 8 時", "test.txt:1:11: expected CLOSE_PAREN got SI (時)"},
+		{"塞 1 入 i。\n塞 2 入 。", "test.txt:2:7: invalid token 。(EOL)"},
+		{"講（\"hi）\n講（1）", "test.txt:1:3: unterminated string"},
+		{"塞 『abc\n\n", "test.txt:1:3: unterminated string"},
+		{"塞 1 入 i。 講（\"\\z\"）", "test.txt:1:12: invalid escape \\z"},
+		{"講（\"a{1 +}\"）", "test.txt:1:9: invalid token (EOF)"},
+		{"塞 1 入 i。\n講（\"{i i}\"）", "test.txt:2:7: expected } got IDENTIFIER (i)"},
+		{"講（\"{}\"）", "test.txt:1:3: empty {} in string, use \\{ for a brace"},
+	}
+	for _, test := range tests {
+		l := lexer.NewFile("test.txt", test.input)
+		p := New(l)
+		p.ParseProgram()
+		if len(p.Errors) == 0 {
+			t.Errorf("expected errors for %q", test.input)
+			continue
+		}
+		if p.Errors[0] != test.expected {
+			t.Errorf("expected error %q got %q", test.expected, p.Errors[0])
+		}
+	}
+}
+
+func TestNodePosition(t *testing.T) {
+	input := "塞 1 入 i。\n  講（i + 2， 【1，2】）。"
+	l := lexer.NewFile("test.txt", input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(p, t)
+	if len(program.Statements) != 2 {
+		t.Fatalf("len(program) expected 2 got %d", len(program.Statements))
+	}
+
+	es, ok := program.Statements[1].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("expected ExpressionStatement got %T", program.Statements[1])
+	}
+	fce, ok := es.Expression.(*ast.FunctionCallExpression)
+	if !ok {
+		t.Fatalf("expected FunctionCallExpression got %T", es.Expression)
+	}
+	if fce.Pos().String() != "test.txt:2:3" {
+		t.Errorf("expected call at test.txt:2:3 got %s", fce.Pos())
+	}
+	if fce.End().String() != "test.txt:2:18" {
+		t.Errorf("expected call end at test.txt:2:18 got %s", fce.End())
+	}
+	if fce.Parameters[0].Pos().String() != "test.txt:2:5" || fce.Parameters[0].End().String() != "test.txt:2:10" {
+		t.Errorf("expected infix at 2:5-2:10 got %s-%s", fce.Parameters[0].Pos(), fce.Parameters[0].End())
+	}
+	if program.Pos().String() != "test.txt:1:1" {
+		t.Errorf("expected program at test.txt:1:1 got %s", program.Pos())
+	}
+}
+
+func TestLoopControl(t *testing.T) {
+	input := `
+	當 （啱） 時，就「
+	    如果 （i 大過 3） 嘅話，就「 停。 」
+	    跳過。
+	」`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(p, t)
+	if len(program.Statements) != 1 {
+		t.Fatalf("len(program) expected 1 got %d", len(program.Statements))
+	}
+	loop, ok := program.Statements[0].(*ast.WhileLoop)
+	if !ok {
+		t.Fatalf("expected WhileLoop got %T", program.Statements[0])
+	}
+	if len(loop.Body.Statements) != 2 {
+		t.Fatalf("expected 2 body statements got %d", len(loop.Body.Statements))
+	}
+	ie := loop.Body.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IfExpression)
+	if _, ok := ie.Consequence.Statements[0].(*ast.BreakStatement); !ok {
+		t.Errorf("expected BreakStatement got %T", ie.Consequence.Statements[0])
+	}
+	if _, ok := loop.Body.Statements[1].(*ast.ContinueStatement); !ok {
+		t.Errorf("expected ContinueStatement got %T", loop.Body.Statements[1])
+	}
+
+	invalid := []string{
+		"停。",
+		"如果 （啱） 嘅話，就「 跳過。 」",
+		`當 （啱） 時，就「
+		    聽到 f（） 嘅話，就「 停。 」
+		」`,
+	}
+	for _, input := range invalid {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+		if len(p.Errors) == 0 {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
+func TestForEachLoop(t *testing.T) {
+	input := `
+	逐個 x 喺 範圍（3） 度，就「
+	    如果 （x 係 1） 嘅話，就「 跳過。 」
+	    講（x）。
+	」`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(p, t)
+	if len(program.Statements) != 1 {
+		t.Fatalf("len(program) expected 1 got %d", len(program.Statements))
+	}
+	loop, ok := program.Statements[0].(*ast.ForEachLoop)
+	if !ok {
+		t.Fatalf("expected ForEachLoop got %T", program.Statements[0])
+	}
+	if loop.Variable.String() != "x" {
+		t.Errorf("expected variable x got %s", loop.Variable)
+	}
+	if loop.Iterable.String() != "範圍(3)" {
+		t.Errorf("expected iterable 範圍(3) got %s", loop.Iterable)
+	}
+	if len(loop.Body.Statements) != 2 {
+		t.Fatalf("expected 2 body statements got %d", len(loop.Body.Statements))
+	}
+
+	invalid := []string{
+		"逐個 喺 arr 度，就「 」",
+		"逐個 x arr 度，就「 」",
+		"逐個 x 喺 arr，就「 」",
+		"逐個 x 喺 arr 度，就「 ",
+	}
+	for _, input := range invalid {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+		if len(p.Errors) == 0 {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
+func TestElseIfStatement(t *testing.T) {
+	input := `如果 （a） 嘅話，就「
+	    1。
+	」唔係就如果 （b） 嘅話，就「
+	    2。
+	」唔係就 如果 （c） 嘅話，就「
+	    3。
+	」唔係就「
+	    4。
+	」`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(p, t)
+	if len(program.Statements) != 1 {
+		t.Fatalf("len(program) expected 1 got %d", len(program.Statements))
+	}
+	ie, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("expected ifExpression got %T", program.Statements[0])
+	}
+	if len(ie.ElseIfs) != 2 {
+		t.Fatalf("expected 2 else ifs got %d", len(ie.ElseIfs))
+	}
+	for i, cond := range []string{"b", "c"} {
+		if ie.ElseIfs[i].Condition.String() != cond {
+			t.Errorf("expected else if condition %s got %s", cond, ie.ElseIfs[i].Condition)
+		}
+	}
+	if ie.Alternative == nil || ie.Alternative.String() != "4" {
+		t.Errorf("expected alternative 4 got %v", ie.Alternative)
+	}
+	if ie.End() != ie.Alternative.End() {
+		t.Errorf("expected if to end at %s got %s", ie.Alternative.End(), ie.End())
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	input := `睇下（x）「
+	    1 就「 “one”。 」
+	    -2.5 就「 “neg”。 」
+	    【a，_】，就「 a。 」
+	    其他 就「 “other”。 」
+	」`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(p, t)
+	if len(program.Statements) != 1 {
+		t.Fatalf("len(program) expected 1 got %d", len(program.Statements))
+	}
+	me, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.MatchExpression)
+	if !ok {
+		t.Fatalf("expected MatchExpression got %T", program.Statements[0])
+	}
+	if me.Value.String() != "x" {
+		t.Errorf("expected value x got %s", me.Value)
+	}
+	patterns := []string{"1", "-2.5", "[a, _]", ""}
+	if len(me.Arms) != len(patterns) {
+		t.Fatalf("expected %d arms got %d", len(patterns), len(me.Arms))
+	}
+	for i, pattern := range patterns {
+		arm := me.Arms[i]
+		if pattern == "" {
+			if arm.Pattern != nil {
+				t.Errorf("arms[%d] expected default got %s", i, arm.Pattern)
+			}
+			continue
+		}
+		if arm.Pattern.String() != pattern {
+			t.Errorf("arms[%d] expected pattern %s got %s", i, pattern, arm.Pattern)
+		}
+		if len(arm.Body.Statements) != 1 {
+			t.Errorf("arms[%d] expected 1 statement got %d", i, len(arm.Body.Statements))
+		}
+	}
+
+	invalid := []string{
+		"睇下（x）「 1 + 1 就「」 」",
+		"睇下（x）「 f（） 就「」 」",
+		"睇下（x）「 1 「」 」",
+		"睇下（x）「 1 就「」",
+		"睇下 x「 1 就「」 」",
+		"睇下（x）「 ） 就「 講（1）。 」 」",
+		"睇下（x）「 【）】 就「 講（1）。 」 」",
+		"睇下（x）「 1 + 就「 講（1）。 」 」",
+	}
+	for _, input := range invalid {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+		if len(p.Errors) == 0 {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
+func TestTryExpression(t *testing.T) {
+	tests := []struct {
+		input     string
+		errorName string
+		str       string
+	}{
+		{"試下「 1。 」出事就（e）「 2。 」", "e", "try1catch(e)2"},
+		{"試下「 1。 」出事就「 2。 」", "", "try1catch2"},
+	}
+	for _, test := range tests {
+		l := lexer.New(test.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(p, t)
+		if len(program.Statements) != 1 {
+			t.Fatalf("len(program) expected 1 got %d", len(program.Statements))
+		}
+		te, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.TryExpression)
+		if !ok {
+			t.Fatalf("expected TryExpression got %T", program.Statements[0])
+		}
+		if test.errorName == "" && te.ErrorName != nil {
+			t.Errorf("expected no error name got %s", te.ErrorName)
+		}
+		if test.errorName != "" && (te.ErrorName == nil || te.ErrorName.String() != test.errorName) {
+			t.Errorf("expected error name %s got %v", test.errorName, te.ErrorName)
+		}
+		if te.String() != test.str {
+			t.Errorf("expected %s got %s", test.str, te.String())
+		}
+	}
+
+	invalid := []string{
+		"試下「 1。 」",
+		"試下「 1。 」出事就（）「」",
+		"試下「 1。 」出事就（e「」",
+		"試下 1 出事就「」",
+	}
+	for _, input := range invalid {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+		if len(p.Errors) == 0 {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
+func TestImportStatement(t *testing.T) {
+	tests := []struct {
+		input string
+		path  string
+		name  string
+	}{
+		{`引入 “lib/數學.txt” 做 m。`, "lib/數學.txt", "m"},
+		{`引入 “lib/數學.txt”`, "lib/數學.txt", "數學"},
+		{`引入 “工具”;`, "工具", "工具"},
+	}
+	for _, test := range tests {
+		l := lexer.New(test.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(p, t)
+		if len(program.Statements) != 1 {
+			t.Fatalf("len(program) expected 1 got %d", len(program.Statements))
+		}
+		is, ok := program.Statements[0].(*ast.ImportStatement)
+		if !ok {
+			t.Fatalf("expected ImportStatement got %T", program.Statements[0])
+		}
+		if is.Path.Value != test.path {
+			t.Errorf("expected path %s got %s", test.path, is.Path.Value)
+		}
+		if is.Name.String() != test.name {
+			t.Errorf("expected name %s got %s", test.name, is.Name)
+		}
+	}
+
+	invalid := []string{
+		"引入 數學",
+		"引入 “數學” 做",
+		"引入 “數學” 做 “m”",
+	}
+	for _, input := range invalid {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+		if len(p.Errors) == 0 {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
+func TestMemberExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"m.x", "(m.x)"},
+		{"m 嘅 x", "(m.x)"},
+		{"m.f（1）", "(m.f)(1)"},
+		{"m.a.b【0】", "((m.a).b)[0]"},
+		{"-m.x + 1", "(-(m.x) + 1)"},
+	}
+	for _, test := range tests {
+		l := lexer.New(test.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(p, t)
+		if program.String() != test.expected {
+			t.Errorf("expected %s got %s", test.expected, program.String())
+		}
+	}
+
+	l := lexer.New("m.1")
+	p := New(l)
+	p.ParseProgram()
+	if len(p.Errors) == 0 {
+		t.Errorf("expected error for m.1")
+	}
+}
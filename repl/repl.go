@@ -8,32 +8,42 @@ import (
 	"cantolang/parser"
 	"fmt"
 	"io"
+	"strings"
 )
 
 const PROMPT = ">> "
 
 func Start(in io.Reader, out io.Writer) {
-	scanner := bufio.NewScanner(in)
+	// programs read input with 聽 from the same reader as the repl
+	reader := bufio.NewReader(in)
 	env := object.NewEnvironment(nil)
+	env.Runtime.Stdout = out
+	env.Runtime.Stderr = out
+	env.Runtime.Stdin = reader
 
 	for {
 		fmt.Fprintf(out, PROMPT)
-		scanned := scanner.Scan()
+		line, err := reader.ReadString('\n')
 
-		if !scanned {
+		if err != nil && line == "" {
 			return
 		}
 
-		line := scanner.Text()
+		line = strings.TrimRight(line, "\r\n")
 		l := lexer.New(line)
 		p := parser.New(l)
 		program := p.ParseProgram()
 
 		if len(p.Errors) != 0 {
-			printParserErrors(out, p.Errors)
+			printParserErrors(env.Runtime.Stderr, p.Errors)
 			continue
 		}
 		evaluated := evaluator.Eval(program, env)
+		if err, ok := evaluated.(*object.Error); ok {
+			io.WriteString(env.Runtime.Stderr, err.Traceback())
+			io.WriteString(env.Runtime.Stderr, "\n")
+			continue
+		}
 		if evaluated != nil {
 			io.WriteString(out, evaluated.Inspect())
 			io.WriteString(out, "\n")
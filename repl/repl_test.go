@@ -0,0 +1,40 @@
+package repl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	in := strings.NewReader("塞 2 入 x。\n講（x，x + 1）。\nx * 5\n1 + 啱\n塞 1 入\n")
+	out := &bytes.Buffer{}
+	Start(in, out)
+
+	expected := []string{
+		PROMPT + "2",
+		PROMPT + "2 3",
+		"NULL",
+		PROMPT + "10",
+		PROMPT + "1:1: *object.Integer (&{Value:1}) + *object.Boolean (&{Value:true}): type mismatch",
+		PROMPT + "\t1:6: invalid token (EOF)",
+	}
+	got := strings.Split(out.String(), "\n")
+	for i, line := range expected {
+		if i >= len(got) || got[i] != line {
+			t.Fatalf("line %d: expected %q got output\n%s", i, line, out.String())
+		}
+	}
+}
+
+func TestStartInput(t *testing.T) {
+	// 聽 reads the line after the one that calls it
+	in := strings.NewReader("塞 聽（） 入 名。\n阿明\n講（“你好，” + 名）。\n")
+	out := &bytes.Buffer{}
+	Start(in, out)
+
+	expected := PROMPT + "阿明\n" + PROMPT + "你好，阿明\nNULL\n" + PROMPT
+	if out.String() != expected {
+		t.Errorf("expected %q got %q", expected, out.String())
+	}
+}
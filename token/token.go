@@ -1,5 +1,7 @@
 package token
 
+import "fmt"
+
 const (
 	OPEN_PAREN    = "OPEN_PAREN"
 	CLOSE_PAREN   = "CLOSE_PAREN"
@@ -10,6 +12,7 @@ const (
 
 	EOL   = "EOL"
 	COMMA = "COMMA"
+	COLON = "COLON"
 
 	TRUE  = "TRUE"
 	FALSE = "FALSE"
@@ -25,31 +28,53 @@ const (
 	ASSIGN = "ASSIGN"
 	TO     = "TO"
 
-	EQUAL_TO     = "EQUAL_TO"
-	LESS_THAN    = "LESS_THAN"
-	GREATER_THAN = "GREATER_THAN"
+	EQUAL_TO      = "EQUAL_TO"
+	NOT_EQUAL_TO  = "NOT_EQUAL_TO"
+	LESS_THAN     = "LESS_THAN"
+	GREATER_THAN  = "GREATER_THAN"
+	LESS_EQUAL    = "LESS_EQUAL"
+	GREATER_EQUAL = "GREATER_EQUAL"
 
 	AND = "AND"
 	OR  = "OR"
 	NOT = "NOT"
 
-	IF   = "IF"
-	ELSE = "ELSE"
-	GEWA = "GEWA"
+	IF      = "IF"
+	ELSE    = "ELSE"
+	ELSE_IF = "ELSE_IF"
+	GEWA    = "GEWA"
+
+	MATCH   = "MATCH"
+	DEFAULT = "DEFAULT"
+
+	TRY   = "TRY"
+	CATCH = "CATCH"
 
 	THEN = "THEN"
 
-	WHILE = "WHILE"
-	SI    = "SI"
+	WHILE    = "WHILE"
+	SI       = "SI"
+	BREAK    = "BREAK"
+	CONTINUE = "CONTINUE"
+	FOREACH  = "FOREACH"
+	HAI      = "HAI"
+	DOU      = "DOU"
 
 	FUNCTION = "FUNCTION"
 	RETURN   = "RETURN"
 
+	IMPORT = "IMPORT"
+	AS     = "AS"
+	DOT    = "DOT"
+
 	IDENTIFIER = "IDENTIFIER"
 	INVALID    = "INVALID"
+	ERROR      = "ERROR" // literal is the message
 	COMMENT    = "COMMENT"
 	NUMBER     = "NUMBER"
+	FLOAT      = "FLOAT"
 	STRING     = "STRING"
+	TEMPLATE   = "TEMPLATE" // string with {expression} parts
 
 	EOF             = "EOF"
 	TEMP_NOT_SYMBOL = "TEMP_NOT_SYMBOL"
@@ -72,9 +97,13 @@ var symbols = map[rune]string{
 
 	'。': EOL,
 	'，': COMMA,
+	'：': COLON,
 
 	';': EOL,
 	',': COMMA,
+	':': COLON,
+	'.': DOT,
+	'．': DOT,
 
 	'+': ADD,
 	'-': MINUS,
@@ -82,27 +111,53 @@ var symbols = map[rune]string{
 	'/': DIVIDE,
 }
 
+// operators made of ascii comparison chars, the lexer matches the longest one
+var operators = map[string]string{
+	"==": EQUAL_TO,
+	"!=": NOT_EQUAL_TO,
+	"<":  LESS_THAN,
+	">":  GREATER_THAN,
+	"<=": LESS_EQUAL,
+	">=": GREATER_EQUAL,
+}
+
 var keywords = map[string]string{
-	"係":   EQUAL_TO,
-	"細過":  LESS_THAN,
-	"大過":  GREATER_THAN,
-	"同埋":  AND,
-	"或者":  OR,
-	"唔係":  NOT,
-	"如果":  IF,
-	"唔係就": ELSE,
-	"嘅話":  GEWA,
-	"大D":  INCREMENT,
-	"細D":  DECREMENT,
-	"就":   THEN,
-	"當":   WHILE,
-	"時":   SI,
-	"塞":   ASSIGN,
-	"入":   TO,
-	"聽到":  FUNCTION,
-	"俾我":  RETURN,
-	"啱":   TRUE,
-	"錯":   FALSE,
+	"係":     EQUAL_TO,
+	"唔係咁":   NOT_EQUAL_TO,
+	"細過":    LESS_THAN,
+	"大過":    GREATER_THAN,
+	"唔大過":   LESS_EQUAL,
+	"唔細過":   GREATER_EQUAL,
+	"同埋":    AND,
+	"或者":    OR,
+	"唔係":    NOT,
+	"如果":    IF,
+	"唔係就":   ELSE,
+	"唔係就如果": ELSE_IF,
+	"睇下":    MATCH,
+	"其他":    DEFAULT,
+	"試下":    TRY,
+	"出事就":   CATCH,
+	"引入":    IMPORT,
+	"做":     AS,
+	"嘅":     DOT,
+	"嘅話":    GEWA,
+	"大D":    INCREMENT,
+	"細D":    DECREMENT,
+	"就":     THEN,
+	"當":     WHILE,
+	"時":     SI,
+	"停":     BREAK,
+	"跳過":    CONTINUE,
+	"逐個":    FOREACH,
+	"喺":     HAI,
+	"度":     DOU,
+	"塞":     ASSIGN,
+	"入":     TO,
+	"聽到":    FUNCTION,
+	"俾我":    RETURN,
+	"啱":     TRUE,
+	"錯":     FALSE,
 
 	"加": ADD,
 	"減": MINUS,
@@ -118,6 +173,14 @@ func LookUpSymbol(symbol rune) string {
 	return ident
 }
 
+func LookUpOperator(operator string) string {
+	ident, ok := operators[operator]
+	if !ok {
+		return TEMP_NOT_SYMBOL
+	}
+	return ident
+}
+
 func LookUpIdent(keyword string) string {
 	ident, ok := keywords[keyword]
 	if !ok {
@@ -126,7 +189,40 @@ func LookUpIdent(keyword string) string {
 	return ident
 }
 
+// Position is a location in the source, lines and columns start at 1,
+// Offset is the byte offset from the start of the input.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+	Offset int
+}
+
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	if !p.IsValid() {
+		return p.File
+	}
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
 type Token struct {
 	TokenType    string
 	TokenLiteral string
+	Pos          Position       // position of the first char
+	End          Position       // position just after the last char
+	Parts        []TemplatePart // parts of a TEMPLATE
+}
+
+// TemplatePart is text, or the source of an expression when IsExpr is set
+type TemplatePart struct {
+	Value  string
+	IsExpr bool
+	Pos    Position
 }